@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/config"
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/DiDinar5/1inch_test_task/infrastructure/ethereum"
+	"github.com/DiDinar5/1inch_test_task/infrastructure/store"
+	"github.com/DiDinar5/1inch_test_task/internal/metrics"
+	"github.com/DiDinar5/1inch_test_task/internal/usecase"
+)
+
+const chainIDTimeout = 10 * time.Second
+
+// chain is everything main builds for one configured network.
+type chain struct {
+	name           string
+	service        *ethereum.EthereumService
+	usecase        domain.UsecaseInterface
+	requestTimeout time.Duration
+	store          *store.BoltStore
+}
+
+func (c *chain) Close() {
+	if c.service != nil {
+		c.service.Close()
+	}
+	if c.store != nil {
+		c.store.Close()
+	}
+}
+
+// newChain connects to the chain's providers, checks they serve the
+// configured chain ID and starts following its head.
+func newChain(ctx context.Context, name string, cfg *config.Config, chainCfg config.EthereumConfig, appMetrics *metrics.Metrics) (*chain, error) {
+	requestTimeout, callTimeout, err := parseTimeouts(chainCfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeouts: %w", err)
+	}
+
+	chainMetrics := appMetrics.Chain(name)
+
+	ethereumOpts, err := ethereumOptions(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+	ethereumOpts = append(ethereumOpts,
+		ethereum.WithCallTimeout(callTimeout),
+		ethereum.WithRPCObserver(chainMetrics),
+		ethereum.WithLogger(slog.With("chain", name)),
+	)
+
+	c := &chain{name: name, requestTimeout: requestTimeout}
+
+	if cfg.Store.Path != "" {
+		path := cfg.Store.Path
+		if len(cfg.Chains) > 0 {
+			path = chainStorePath(path, name)
+		}
+
+		c.store, err = store.NewBoltStore(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open state store: %w", err)
+		}
+		ethereumOpts = append(ethereumOpts, ethereum.WithStateStore(c.store))
+	}
+
+	c.service, err = ethereum.NewEthereumService(chainCfg.RPCURL, ethereumOpts...)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to initialize Ethereum service: %w", err)
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, chainIDTimeout)
+	defer cancel()
+	if err := c.service.VerifyChainID(verifyCtx); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	usecaseOpts, err := usecaseOptions(cfg.Guard, chainCfg)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	usecaseOpts = append(usecaseOpts, usecase.WithQuoteObserver(chainMetrics))
+
+	// Close waits for the goroutines Start launches, so nothing may fail after it.
+	c.service.Start(ctx)
+	chainMetrics.RegisterCacheStats(c.service.CacheStats)
+	chainMetrics.RegisterBreakerStatus(c.service.BreakerStatus)
+	chainMetrics.RegisterLimiterStats(c.service.LimiterStats)
+
+	c.usecase = usecase.NewUsecase(c.service, usecaseOpts...)
+
+	return c, nil
+}
+
+// chainStorePath gives every chain its own state file next to the configured one.
+func chainStorePath(path, chain string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + chain + ext
+}
+
+func ethereumOptions(cfg config.EthereumConfig) ([]ethereum.Option, error) {
+	var opts []ethereum.Option
+
+	if cfg.TokenOverrides != "" {
+		overrides, err := ethereum.LoadTokenOverrides(cfg.TokenOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load token overrides: %w", err)
+		}
+		opts = append(opts, ethereum.WithTokenOverrides(overrides))
+	}
+
+	if cfg.HeadPollInterval != "" {
+		interval, err := time.ParseDuration(cfg.HeadPollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid head poll interval: %w", err)
+		}
+		opts = append(opts, ethereum.WithHeadPollInterval(interval))
+	}
+
+	opts = append(opts, ethereum.WithSyncLogs(cfg.SyncLogs))
+	opts = append(opts, ethereum.WithChainID(cfg.ChainID))
+
+	if cfg.MaxBlockAge != "" {
+		maxBlockAge, err := time.ParseDuration(cfg.MaxBlockAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max block age: %w", err)
+		}
+		opts = append(opts, ethereum.WithMaxBlockAge(maxBlockAge))
+	}
+
+	if cfg.Retry.MaxAttempts > 0 {
+		baseDelay, err := time.ParseDuration(cfg.Retry.BaseDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry base delay: %w", err)
+		}
+		maxDelay, err := time.ParseDuration(cfg.Retry.MaxDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry max delay: %w", err)
+		}
+		opts = append(opts, ethereum.WithRetryPolicy(ethereum.RetryPolicy{
+			MaxAttempts: cfg.Retry.MaxAttempts,
+			BaseDelay:   baseDelay,
+			MaxDelay:    maxDelay,
+		}))
+	}
+
+	if cfg.Breaker.FailureThreshold > 0 {
+		openTimeout, err := time.ParseDuration(cfg.Breaker.OpenTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid breaker open timeout: %w", err)
+		}
+		opts = append(opts, ethereum.WithCircuitBreaker(ethereum.BreakerConfig{
+			FailureThreshold: cfg.Breaker.FailureThreshold,
+			OpenTimeout:      openTimeout,
+			HalfOpenMaxCalls: cfg.Breaker.HalfOpenMaxCalls,
+		}))
+	}
+
+	if cfg.Hedge.Enabled {
+		minDelay, err := time.ParseDuration(cfg.Hedge.MinDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedge min delay: %w", err)
+		}
+		opts = append(opts, ethereum.WithHedging(ethereum.HedgeConfig{
+			Quantile:      cfg.Hedge.Quantile,
+			MinDelay:      minDelay,
+			BudgetPercent: cfg.Hedge.BudgetPercent,
+		}))
+	}
+
+	opts = append(opts, ethereum.WithQuorum(ethereum.QuorumConfig{
+		Providers: cfg.Quorum.Providers,
+		Required:  cfg.Quorum.Required,
+	}))
+
+	if cfg.RateLimit.UnitsPerSecond > 0 {
+		var maxWait time.Duration
+		if cfg.RateLimit.MaxWait != "" {
+			var err error
+			maxWait, err = time.ParseDuration(cfg.RateLimit.MaxWait)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate limit max wait: %w", err)
+			}
+		}
+		opts = append(opts, ethereum.WithRateLimit(ethereum.RateLimitConfig{
+			UnitsPerSecond: cfg.RateLimit.UnitsPerSecond,
+			Burst:          cfg.RateLimit.Burst,
+			MaxWait:        maxWait,
+			Costs:          cfg.RateLimit.Costs,
+		}))
+	}
+
+	if cfg.Cache.Capacity > 0 {
+		var err error
+		cacheConfig := ethereum.CacheConfig{Capacity: cfg.Cache.Capacity}
+		if cfg.Cache.TTL != "" {
+			cacheConfig.TTL, err = time.ParseDuration(cfg.Cache.TTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cache TTL: %w", err)
+			}
+		}
+		if cfg.Cache.NegativeTTL != "" {
+			cacheConfig.NegativeTTL, err = time.ParseDuration(cfg.Cache.NegativeTTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cache negative TTL: %w", err)
+			}
+		}
+		opts = append(opts, ethereum.WithCacheConfig(cacheConfig))
+	}
+
+	if len(cfg.Providers) > 0 {
+		providers := make([]ethereum.ProviderConfig, 0, len(cfg.Providers))
+		for _, provider := range cfg.Providers {
+			providers = append(providers, ethereum.ProviderConfig{
+				Name:     provider.Name,
+				URL:      provider.URL,
+				Priority: provider.Priority,
+				Weight:   provider.Weight,
+			})
+		}
+		opts = append(opts, ethereum.WithProviders(providers))
+	}
+
+	return opts, nil
+}
+
+func usecaseOptions(guard config.GuardConfig, cfg config.EthereumConfig) ([]usecase.Option, error) {
+	opts := []usecase.Option{
+		usecase.WithNativeToken(cfg.NativeToken, cfg.WrappedNative),
+	}
+
+	factoryFees := make(map[string]uint64, len(cfg.Factories))
+	for _, factory := range cfg.Factories {
+		factoryFees[factory.Address] = factory.FeeBps
+	}
+	opts = append(opts, usecase.WithFees(cfg.FeeBps, factoryFees))
+
+	if guard.TWAPWindow != "" {
+		window, err := time.ParseDuration(guard.TWAPWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TWAP guard window: %w", err)
+		}
+		opts = append(opts, usecase.WithTWAPGuard(window, guard.MaxDeviationBps))
+	}
+
+	return opts, nil
+}
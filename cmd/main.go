@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,34 +12,104 @@ import (
 	"time"
 
 	"github.com/DiDinar5/1inch_test_task/config"
-	"github.com/DiDinar5/1inch_test_task/infrastructure/ethereum"
+	"github.com/DiDinar5/1inch_test_task/domain"
 	"github.com/DiDinar5/1inch_test_task/internal/handler"
-	validator "github.com/DiDinar5/1inch_test_task/internal/middlewares"
-	"github.com/DiDinar5/1inch_test_task/internal/usecase"
+	"github.com/DiDinar5/1inch_test_task/internal/logging"
+	"github.com/DiDinar5/1inch_test_task/internal/metrics"
+	"github.com/DiDinar5/1inch_test_task/internal/middlewares"
+	"github.com/DiDinar5/1inch_test_task/internal/tracing"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
-	cfg := config.Load()
+	configPath := flag.String("config", envOr(config.ConfigPathEnv, "config.yaml"), "path to the YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+	flag.Parse()
 
-	ethereumService, err := ethereum.NewEthereumService(cfg.Ethereum.RPCURL)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to initialize Ethereum service: %v", err)
+		// The logger is configured from this file, so report every problem plainly.
+		fmt.Fprintf(os.Stderr, "Invalid configuration in %s:\n%v\n", *configPath, err)
+		os.Exit(1)
 	}
 
-	usecaseInstance := usecase.NewUsecase(ethereumService)
+	if *printConfig {
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg.Redacted()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print configuration: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	handlerInstance := handler.NewHandler(usecaseInstance)
+	logger, err := logging.New(os.Stdout, cfg.Log.Level)
+	if err != nil {
+		fatal("Invalid log configuration", err)
+	}
+	slog.SetDefault(logger)
+	slog.Info("Loaded configuration", "path", *configPath, "config", cfg)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		File:        cfg.Tracing.File,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
+	appMetrics := metrics.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chainConfigs := cfg.ChainConfigs()
+
+	chains := make([]*chain, 0, len(chainConfigs))
+	usecases := make(map[string]domain.UsecaseInterface, len(chainConfigs))
+	handlerOpts := make([]handler.Option, 0, len(chainConfigs))
+	for _, name := range cfg.ChainNames() {
+		chain, err := newChain(ctx, name, cfg, chainConfigs[name], appMetrics)
+		if err != nil {
+			fatal("Failed to initialize chain", fmt.Errorf("%s: %w", name, err))
+		}
+		chains = append(chains, chain)
+
+		usecases[name] = chain.usecase
+		handlerOpts = append(handlerOpts, handler.WithRequestTimeout(name, chain.requestTimeout))
+		slog.Info("Chain initialized", "chain", name, "chain_id", chainConfigs[name].ChainID)
+	}
+
+	if len(cfg.Server.AllowedOrigins) > 0 {
+		handlerOpts = append(handlerOpts, handler.WithAllowedOrigins(cfg.Server.AllowedOrigins...))
+	}
+
+	handlerInstance := handler.NewHandler(usecases, cfg.DefaultChainName(), handlerOpts...)
 
 	e := echo.New()
 	e.HideBanner = true
 
-	e.Validator = validator.NewValidator()
-	e.Use(middleware.Logger())
+	e.Validator = middlewares.NewValidator()
+	e.Use(middlewares.RequestID())
+	e.Use(tracing.Middleware())
+	e.Use(middlewares.RequestLogger(logger))
+	e.Use(appMetrics.Middleware())
 	e.Use(middleware.Recover())
 
 	handlerInstance.SetupRoutes(e)
+	e.GET("/metrics", echo.WrapHandler(appMetrics.Handler()))
 
 	server := &http.Server{
 		Addr:    cfg.Server.Host + ":" + cfg.Server.Port,
@@ -45,28 +117,80 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("Starting server on %s:%s", cfg.Server.Host, cfg.Server.Port)
+		slog.Info("Starting server", "address", cfg.Server.Host+":"+cfg.Server.Port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
 		}
 	}()
 
-	gracefulShutdown(server)
+	var shutdownDelay time.Duration
+	if cfg.Server.ShutdownDelay != "" {
+		shutdownDelay, err = time.ParseDuration(cfg.Server.ShutdownDelay)
+		if err != nil {
+			fatal("Invalid shutdown delay", err)
+		}
+	}
+
+	gracefulShutdown(server, handlerInstance, shutdownDelay)
+
+	// Head followers still write to the stores until they see the cancellation.
+	cancel()
+	for _, chain := range chains {
+		chain.Close()
+	}
+}
+
+func parseTimeouts(cfg config.EthereumConfig) (time.Duration, time.Duration, error) {
+	requestTimeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid timeout: %w", err)
+	}
+
+	callTimeout, err := time.ParseDuration(cfg.CallTimeout)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid call_timeout: %w", err)
+	}
+
+	if requestTimeout <= 0 || callTimeout <= 0 {
+		return 0, 0, fmt.Errorf("timeouts must be positive")
+	}
+	if callTimeout > requestTimeout {
+		return 0, 0, fmt.Errorf("call_timeout %s exceeds timeout %s", callTimeout, requestTimeout)
+	}
+
+	return requestTimeout, callTimeout, nil
 }
 
-func gracefulShutdown(server *http.Server) {
+func gracefulShutdown(server *http.Server, handlerInstance *handler.Handler, delay time.Duration) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server", "drain_delay", delay.String())
+
+	// Fail readiness first so traffic drains before the listener closes.
+	handlerInstance.Drain()
+	time.Sleep(delay)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", err)
 	}
 
-	log.Println("Server exited")
+	slog.Info("Server exited")
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// fatal logs err and exits, skipping deferred cleanup just like log.Fatal.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
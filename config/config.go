@@ -1,63 +1,264 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Ethereum EthereumConfig `yaml:"ethereum"`
+	Server ServerConfig `yaml:"server"`
+	// Chains maps the name used in request paths to that chain's settings.
+	Chains map[string]EthereumConfig `yaml:"chains,omitempty"`
+	// DefaultChain serves requests that do not name a chain.
+	DefaultChain string `yaml:"default_chain,omitempty"`
+	// Ethereum is served as the single chain "ethereum" when Chains is empty.
+	Ethereum EthereumConfig `yaml:"ethereum,omitempty"`
+	Store    StoreConfig    `yaml:"store"`
+	Guard    GuardConfig    `yaml:"guard"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Log      LogConfig      `yaml:"log"`
+}
+
+const legacyChainName = "ethereum"
+
+// ChainConfigs returns every configured chain by lowercase name.
+func (c *Config) ChainConfigs() map[string]EthereumConfig {
+	if len(c.Chains) == 0 {
+		return map[string]EthereumConfig{legacyChainName: c.Ethereum}
+	}
+
+	chains := make(map[string]EthereumConfig, len(c.Chains))
+	for name, chain := range c.Chains {
+		chains[strings.ToLower(name)] = chain
+	}
+	return chains
+}
+
+// ChainNames returns the configured chain names in a stable order.
+func (c *Config) ChainNames() []string {
+	chains := c.ChainConfigs()
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultChainName is DefaultChain if set, otherwise "ethereum" when it is
+// configured, otherwise the first chain by name.
+func (c *Config) DefaultChainName() string {
+	if c.DefaultChain != "" {
+		return strings.ToLower(c.DefaultChain)
+	}
+
+	names := c.ChainNames()
+	for _, name := range names {
+		if name == legacyChainName {
+			return name
+		}
+	}
+	return names[0]
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level"`
+}
+
+// TracingConfig selects where spans go: "otlp" sends them to Endpoint,
+// "stdout" and "file" write JSON for offline use, "none" disables tracing.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	File        string  `yaml:"file"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
 }
 
 type ServerConfig struct {
 	Port string `yaml:"port"`
 	Host string `yaml:"host"`
+	// ShutdownDelay keeps serving while reporting not ready, giving load
+	// balancers time to stop routing traffic before the listener closes.
+	ShutdownDelay string `yaml:"shutdown_delay"`
+	// AllowedOrigins are the browser origins, besides the server's own, that may
+	// open websocket streams; "*" allows any.
+	AllowedOrigins []string `yaml:"allowed_origins,omitempty"`
 }
 
-type EthereumConfig struct {
-	RPCURL  string `yaml:"rpc_url"`
-	Timeout string `yaml:"timeout"`
+type StoreConfig struct {
+	Path string `yaml:"path"`
 }
 
-func Load() *Config {
-	config, err := loadFromYAML("config.yaml")
-	if err != nil {
-		return defaultConfig()
-	}
+// GuardConfig enables the spot-vs-TWAP check on estimates when TWAPWindow is
+// set. It is off by default: the check needs an archive node for the window start.
+type GuardConfig struct {
+	TWAPWindow      string `yaml:"twap_window"`
+	MaxDeviationBps uint64 `yaml:"max_deviation_bps"`
+}
 
-	return config
+type EthereumConfig struct {
+	RPCURL string `yaml:"rpc_url"`
+	// Timeout is the overall deadline for one estimate; CallTimeout bounds
+	// each RPC call to a single provider.
+	Timeout          string `yaml:"timeout"`
+	CallTimeout      string `yaml:"call_timeout"`
+	TokenOverrides   string `yaml:"token_overrides"`
+	ChainID          uint64 `yaml:"chain_id"`
+	NativeToken      string `yaml:"native_token"`
+	WrappedNative    string `yaml:"wrapped_native"`
+	HeadPollInterval string `yaml:"head_poll_interval"`
+	// MaxBlockAge is how old the latest block may be before readiness fails.
+	MaxBlockAge string `yaml:"max_block_age"`
+	SyncLogs    bool   `yaml:"sync_logs"`
+	// Providers takes precedence over RPCURL when set.
+	Providers []ProviderConfig `yaml:"providers"`
+	Retry     RetryConfig      `yaml:"retry"`
+	Breaker   BreakerConfig    `yaml:"breaker"`
+	Hedge     HedgeConfig      `yaml:"hedge"`
+	Quorum    QuorumConfig     `yaml:"quorum"`
+	RateLimit RateLimitConfig  `yaml:"rate_limit"`
+	Cache     CacheConfig      `yaml:"cache"`
+	// FeeBps is the swap fee in basis points. Factories, when set, restricts
+	// quotes to pools deployed by them and supplies each one's fee instead.
+	FeeBps    uint64          `yaml:"fee_bps"`
+	Factories []FactoryConfig `yaml:"factories"`
 }
 
-func loadFromYAML(filename string) (*Config, error) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file %s not found", filename)
-	}
+// UnmarshalYAML fills settings a chain leaves out with the defaults, except
+// for the ones that only make sense per chain: chain ID and the wrapped-native
+// token. There is no default RPC URL.
+func (c *EthereumConfig) UnmarshalYAML(value *yaml.Node) error {
+	type chainConfig EthereumConfig
+
+	defaults := defaultEthereumConfig()
+	defaults.ChainID = 0
+	defaults.WrappedNative = ""
 
-	data, err := os.ReadFile(filename)
+	// Node.Decode does not inherit KnownFields, so the section is decoded
+	// again strictly to keep rejecting misspelled keys inside a chain.
+	data, err := yaml.Marshal(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	config := chainConfig(defaults)
+	if err := decoder.Decode(&config); err != nil {
+		return fmt.Errorf("in section at line %d: %w", value.Line, err)
 	}
 
-	return &config, nil
+	*c = EthereumConfig(config)
+	return nil
+}
+
+type FactoryConfig struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+	FeeBps  uint64 `yaml:"fee_bps"`
+}
+
+// CacheConfig bounds the pool token and token metadata caches. Failed
+// lookups are remembered for NegativeTTL.
+type CacheConfig struct {
+	Capacity    int    `yaml:"capacity"`
+	TTL         string `yaml:"ttl"`
+	NegativeTTL string `yaml:"negative_ttl"`
+}
+
+// RateLimitConfig is a per-provider compute-unit budget; zero disables it.
+type RateLimitConfig struct {
+	UnitsPerSecond float64        `yaml:"units_per_second"`
+	Burst          int            `yaml:"burst"`
+	MaxWait        string         `yaml:"max_wait"`
+	Costs          map[string]int `yaml:"costs"`
+}
+
+// QuorumConfig left at zero asks up to 3 providers and requires a majority.
+type QuorumConfig struct {
+	Providers int `yaml:"providers"`
+	Required  int `yaml:"required"`
+}
+
+type HedgeConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	Quantile      float64 `yaml:"quantile"`
+	MinDelay      string  `yaml:"min_delay"`
+	BudgetPercent float64 `yaml:"budget_percent"`
+}
+
+type BreakerConfig struct {
+	FailureThreshold int    `yaml:"failure_threshold"`
+	OpenTimeout      string `yaml:"open_timeout"`
+	HalfOpenMaxCalls int    `yaml:"half_open_max_calls"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int    `yaml:"max_attempts"`
+	BaseDelay   string `yaml:"base_delay"`
+	MaxDelay    string `yaml:"max_delay"`
+}
+
+type ProviderConfig struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`
+	Priority int    `yaml:"priority"`
+	Weight   int    `yaml:"weight"`
 }
 
 func defaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Host: "localhost",
-			Port: "1337",
+			Host:          "localhost",
+			Port:          "1337",
+			ShutdownDelay: "5s",
+		},
+		Ethereum: defaultEthereumConfig(),
+		Guard: GuardConfig{
+			MaxDeviationBps: 500,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "estimator",
+			SampleRatio: 1,
+		},
+	}
+}
+
+func defaultEthereumConfig() EthereumConfig {
+	return EthereumConfig{
+		Timeout:          "30s",
+		CallTimeout:      "5s",
+		ChainID:          1,
+		NativeToken:      "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE",
+		WrappedNative:    "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		HeadPollInterval: "2s",
+		MaxBlockAge:      "2m",
+		Retry: RetryConfig{
+			MaxAttempts: 3,
+			BaseDelay:   "100ms",
+			MaxDelay:    "2s",
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: 5,
+			OpenTimeout:      "30s",
+			HalfOpenMaxCalls: 1,
 		},
-		Ethereum: EthereumConfig{
-			RPCURL:  "http://localhost:8545",
-			Timeout: "30s",
+		Cache: CacheConfig{
+			Capacity:    10000,
+			TTL:         "24h",
+			NegativeTTL: "1m",
 		},
+		FeeBps: 30,
 	}
 }
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const envPrefix = "APP"
+
+// applyEnv overrides settings from APP_<PATH> variables, where PATH is the
+// YAML path upper-cased and joined with underscores: APP_SERVER_PORT,
+// APP_ETHEREUM_RPC_URL, APP_CHAINS_BSC_RPC_URL or, for list entries,
+// APP_CHAINS_BSC_PROVIDERS_0_URL. Chains and list entries must already exist
+// in the file. Once chains are configured, APP_ETHEREUM_* variables apply to
+// the chain named ethereum and are rejected if there is none. Unknown APP_
+// variables are reported so typos do not go unnoticed.
+func applyEnv(config *Config, environ []string) []error {
+	vars := make(map[string]string)
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if ok && strings.HasPrefix(name, envPrefix+"_") {
+			vars[name] = value
+		}
+	}
+
+	used := map[string]bool{ConfigPathEnv: true}
+	var errs []error
+
+	legacy := make(map[string]string)
+	if len(config.Chains) > 0 {
+		legacy, errs = mapLegacyEnv(config, vars)
+	}
+
+	applyEnvValue(reflect.ValueOf(config).Elem(), envPrefix, vars, used, &errs)
+
+	for name, replacement := range legacy {
+		used[name] = used[replacement]
+		delete(vars, replacement)
+	}
+
+	unknown := make([]string, 0)
+	for name := range vars {
+		if !used[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs = append(errs, fmt.Errorf("%s: unknown configuration variable", name))
+	}
+
+	return errs
+}
+
+// mapLegacyEnv points APP_ETHEREUM_* variables at the ethereum chain, since
+// the ethereum section is not served once chains are configured. It returns
+// each legacy name with the variable it was copied to.
+func mapLegacyEnv(config *Config, vars map[string]string) (map[string]string, []error) {
+	prefix := envPrefix + "_ETHEREUM_"
+	_, hasChain := config.Chains["ethereum"]
+
+	names := make([]string, 0)
+	for name := range vars {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	legacy := make(map[string]string, len(names))
+	var errs []error
+	for _, name := range names {
+		field := strings.TrimPrefix(name, prefix)
+		replacement := envPrefix + "_CHAINS_ETHEREUM_" + field
+		existing, set := vars[replacement]
+		switch {
+		case !hasChain:
+			errs = append(errs, fmt.Errorf("%s: no ethereum chain is configured; set %s_CHAINS_<NAME>_%s instead", name, envPrefix, field))
+		case set && existing != vars[name]:
+			errs = append(errs, fmt.Errorf("%s: conflicts with %s", name, replacement))
+		default:
+			vars[replacement] = vars[name]
+			legacy[name] = replacement
+			continue
+		}
+		delete(vars, name)
+	}
+
+	return legacy, errs
+}
+
+func applyEnvValue(v reflect.Value, name string, vars map[string]string, used map[string]bool, errs *[]error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			applyEnvValue(v.Field(i), name+"_"+envName(tag), vars, used, errs)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			applyEnvValue(v.Index(i), name+"_"+strconv.Itoa(i), vars, used, errs)
+		}
+	case reflect.Map:
+		// Only maps of sections, such as chains; plain maps like rate limit
+		// costs are left to the file.
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			applyEnvValue(elem, name+"_"+envName(key.String()), vars, used, errs)
+			v.SetMapIndex(key, elem)
+		}
+	default:
+		raw, ok := vars[name]
+		if !ok {
+			return
+		}
+		used[name] = true
+		if err := setFromString(v, raw); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+func setFromString(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot be set from the environment")
+	}
+	return nil
+}
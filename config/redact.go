@@ -0,0 +1,88 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "***"
+
+// Redacted returns a copy that is safe to print. Providers embed API keys in
+// the URL path, query or user info, so only the scheme and host are kept.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.Tracing.Endpoint = redactURL(c.Tracing.Endpoint)
+
+	if len(c.Chains) == 0 {
+		out.Ethereum = redactChain(c.Ethereum)
+		return &out
+	}
+
+	// The legacy section is ignored when chains are set, so leave it out.
+	out.Ethereum = EthereumConfig{}
+	out.Chains = make(map[string]EthereumConfig, len(c.Chains))
+	for name, chain := range c.Chains {
+		out.Chains[name] = redactChain(chain)
+	}
+
+	return &out
+}
+
+// LogValue makes logging a Config print its redacted form.
+func (c *Config) LogValue() slog.Value {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return slog.StringValue("failed to encode config: " + err.Error())
+	}
+
+	var fields map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return slog.StringValue("failed to encode config: " + err.Error())
+	}
+
+	return slog.AnyValue(fields)
+}
+
+func redactChain(chain EthereumConfig) EthereumConfig {
+	chain.RPCURL = redactURL(chain.RPCURL)
+
+	providers := make([]ProviderConfig, len(chain.Providers))
+	for i, provider := range chain.Providers {
+		provider.URL = redactURL(provider.URL)
+		providers[i] = provider
+	}
+	if chain.Providers != nil {
+		chain.Providers = providers
+	}
+
+	return chain
+}
+
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+
+	var b strings.Builder
+	b.WriteString(u.Scheme + "://")
+	if u.User != nil {
+		b.WriteString(redacted + "@")
+	}
+	b.WriteString(u.Host)
+	if u.Path != "" && u.Path != "/" {
+		b.WriteString("/" + redacted)
+	}
+	if u.RawQuery != "" {
+		b.WriteString("?" + redacted)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const maxBps = 10000
+
+var (
+	chainNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+	// reservedChainNames would shadow the unprefixed routes under /:chain.
+	reservedChainNames = map[string]bool{
+		"estimate": true, "twap": true, "status": true,
+		"healthz": true, "readyz": true, "metrics": true,
+	}
+)
+
+// validator collects problems by YAML path instead of stopping at the first.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) problem(field, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.problem(field, "is required")
+		return false
+	}
+	return true
+}
+
+// duration checks an optional non-negative duration.
+func (v *validator) duration(field, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		v.problem(field, "invalid duration %q", value)
+		return 0
+	}
+	if d < 0 {
+		v.problem(field, "must not be negative, got %s", value)
+	}
+	return d
+}
+
+func (v *validator) positiveDuration(field, value string) time.Duration {
+	if !v.required(field, value) {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		v.problem(field, "invalid duration %q", value)
+		return 0
+	}
+	if d <= 0 {
+		v.problem(field, "must be positive, got %s", value)
+	}
+	return d
+}
+
+func (v *validator) url(field, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		// The value is not echoed since it may carry an API key.
+		v.problem(field, "invalid URL, expected scheme://host/path")
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.problem(field, "URL scheme must be one of %s, got %q", strings.Join(schemes, ", "), u.Scheme)
+}
+
+func (v *validator) address(field, value string) {
+	if value != "" && !common.IsHexAddress(value) {
+		v.problem(field, "invalid address %q", value)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.problem(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// Validate reports every invalid setting at once, each prefixed with its YAML path.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		v.problem("server.port", "must be a number between 1 and 65535, got %q", c.Server.Port)
+	}
+	v.duration("server.shutdown_delay", c.Server.ShutdownDelay)
+	for i, origin := range c.Server.AllowedOrigins {
+		if origin != "*" {
+			v.url(fmt.Sprintf("server.allowed_origins[%d]", i), origin, "http", "https")
+		}
+	}
+
+	if len(c.Chains) == 0 {
+		v.chain("ethereum", c.Ethereum)
+	} else {
+		names := make([]string, 0, len(c.Chains))
+		for name := range c.Chains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			field := "chains." + name
+			if !chainNamePattern.MatchString(name) {
+				v.problem(field, "chain name must be lowercase letters, digits, '-' or '_'")
+			} else if reservedChainNames[name] {
+				v.problem(field, "chain name %q clashes with a route", name)
+			}
+			v.chain(field, c.Chains[name])
+		}
+	}
+	if _, ok := c.ChainConfigs()[c.DefaultChainName()]; !ok {
+		v.problem("default_chain", "chain %q is not configured", c.DefaultChain)
+	}
+
+	v.duration("guard.twap_window", c.Guard.TWAPWindow)
+	if c.Guard.MaxDeviationBps > maxBps {
+		v.problem("guard.max_deviation_bps", "must be at most %d, got %d", maxBps, c.Guard.MaxDeviationBps)
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "otlp", "stdout", "file")
+	if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint != "" {
+		v.url("tracing.endpoint", c.Tracing.Endpoint, "http", "https")
+	}
+	if c.Tracing.Exporter == "file" {
+		v.required("tracing.file", c.Tracing.File)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.problem("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		v.problem("log.level", "must be one of debug, info, warn, error, got %q", c.Log.Level)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+func (v *validator) chain(prefix string, c EthereumConfig) {
+	if len(c.Providers) == 0 {
+		if v.required(prefix+".rpc_url", c.RPCURL) {
+			v.url(prefix+".rpc_url", c.RPCURL, "http", "https", "ws", "wss")
+		}
+	} else if c.RPCURL != "" {
+		v.url(prefix+".rpc_url", c.RPCURL, "http", "https", "ws", "wss")
+	}
+
+	providerNames := make(map[string]bool, len(c.Providers))
+	for i, provider := range c.Providers {
+		field := fmt.Sprintf("%s.providers[%d]", prefix, i)
+		if v.required(field+".name", provider.Name) {
+			if providerNames[provider.Name] {
+				v.problem(field+".name", "duplicate provider %q", provider.Name)
+			}
+			providerNames[provider.Name] = true
+		}
+		if v.required(field+".url", provider.URL) {
+			v.url(field+".url", provider.URL, "http", "https", "ws", "wss")
+		}
+		if provider.Weight < 0 {
+			v.problem(field+".weight", "must not be negative, got %d", provider.Weight)
+		}
+	}
+
+	if c.ChainID == 0 {
+		v.problem(prefix+".chain_id", "is required")
+	}
+
+	timeout := v.positiveDuration(prefix+".timeout", c.Timeout)
+	callTimeout := v.positiveDuration(prefix+".call_timeout", c.CallTimeout)
+	if timeout > 0 && callTimeout > timeout {
+		v.problem(prefix+".call_timeout", "%s exceeds timeout %s", callTimeout, timeout)
+	}
+	v.duration(prefix+".head_poll_interval", c.HeadPollInterval)
+	v.duration(prefix+".max_block_age", c.MaxBlockAge)
+
+	v.address(prefix+".native_token", c.NativeToken)
+	v.address(prefix+".wrapped_native", c.WrappedNative)
+
+	if c.Retry.MaxAttempts > 0 {
+		v.positiveDuration(prefix+".retry.base_delay", c.Retry.BaseDelay)
+		v.positiveDuration(prefix+".retry.max_delay", c.Retry.MaxDelay)
+	}
+	if c.Breaker.FailureThreshold > 0 {
+		v.positiveDuration(prefix+".breaker.open_timeout", c.Breaker.OpenTimeout)
+	}
+	if c.Hedge.Enabled {
+		v.duration(prefix+".hedge.min_delay", c.Hedge.MinDelay)
+		if c.Hedge.Quantile < 0 || c.Hedge.Quantile >= 1 {
+			v.problem(prefix+".hedge.quantile", "must be below 1, got %v", c.Hedge.Quantile)
+		}
+		if c.Hedge.BudgetPercent < 0 || c.Hedge.BudgetPercent > 100 {
+			v.problem(prefix+".hedge.budget_percent", "must be between 0 and 100, got %v", c.Hedge.BudgetPercent)
+		}
+	}
+	if c.Quorum.Providers < 0 {
+		v.problem(prefix+".quorum.providers", "must not be negative, got %d", c.Quorum.Providers)
+	}
+	if c.Quorum.Required < 0 {
+		v.problem(prefix+".quorum.required", "must not be negative, got %d", c.Quorum.Required)
+	}
+	if available := max(1, len(c.Providers)); c.Quorum.Required > available {
+		v.problem(prefix+".quorum.required", "%d exceeds the %d configured providers", c.Quorum.Required, available)
+	} else if c.Quorum.Providers > 0 && c.Quorum.Required > c.Quorum.Providers {
+		v.problem(prefix+".quorum.required", "%d exceeds providers %d", c.Quorum.Required, c.Quorum.Providers)
+	}
+	if c.RateLimit.UnitsPerSecond < 0 {
+		v.problem(prefix+".rate_limit.units_per_second", "must not be negative, got %v", c.RateLimit.UnitsPerSecond)
+	}
+	v.duration(prefix+".rate_limit.max_wait", c.RateLimit.MaxWait)
+	if c.Cache.Capacity < 0 {
+		v.problem(prefix+".cache.capacity", "must not be negative, got %d", c.Cache.Capacity)
+	}
+	v.duration(prefix+".cache.ttl", c.Cache.TTL)
+	v.duration(prefix+".cache.negative_ttl", c.Cache.NegativeTTL)
+
+	if c.FeeBps >= maxBps {
+		v.problem(prefix+".fee_bps", "must be below %d, got %d", maxBps, c.FeeBps)
+	}
+	for i, factory := range c.Factories {
+		field := fmt.Sprintf("%s.factories[%d]", prefix, i)
+		if v.required(field+".address", factory.Address) {
+			v.address(field+".address", factory.Address)
+		}
+		if factory.FeeBps >= maxBps {
+			v.problem(field+".fee_bps", "must be below %d, got %d", maxBps, factory.FeeBps)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID attaches the ID of the HTTP request being served, so logs
+// written further down the call chain can be correlated with it.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
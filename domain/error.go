@@ -1,7 +1,52 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrTokenNotInPool = fmt.Errorf("%w: token is not part of the pool", ErrInvalidRequest)
+	ErrPriceDeviation = errors.New("spot price deviates from TWAP")
+
+	ErrExecutionReverted   = fmt.Errorf("%w: execution reverted", ErrInvalidRequest)
+	ErrUpstreamUnavailable = errors.New("upstream RPC unavailable")
+	ErrCircuitOpen         = fmt.Errorf("%w: circuit breaker is open", ErrUpstreamUnavailable)
+	ErrQuorumNotReached    = errors.New("provider quorum not reached")
+)
+
 type ErrorResponse struct {
 	Error       string `json:"error"`
 	Code        int    `json:"code"`
 	Description string `json:"description"`
 }
+
+// RetryAfterError tells the client when the failed request is worth retrying.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// QuorumError reports how providers voted when too few of them agreed.
+type QuorumError struct {
+	Report *QuorumReport
+}
+
+func (e *QuorumError) Error() string {
+	return fmt.Sprintf("%s: %d of %d providers agreed at block %s, %d required",
+		ErrQuorumNotReached, e.Report.Agreed, e.Report.Queried, e.Report.BlockHash, e.Report.Required)
+}
+
+func (e *QuorumError) Unwrap() error {
+	return ErrQuorumNotReached
+}
@@ -5,8 +5,22 @@ type EstimateRequest struct {
 	Src       string `json:"src" validate:"required"`
 	Dst       string `json:"dst" validate:"required"`
 	SrcAmount string `json:"src_amount" validate:"required"`
+	// Strict refuses the quote instead of warning when the TWAP guard trips.
+	Strict bool `json:"strict"`
+	// Quorum confirms reserves with several providers before quoting.
+	Quorum bool `json:"quorum"`
 }
 
 type EstimateResponse struct {
+	Src       string `json:"src"`
+	Dst       string `json:"dst"`
 	DstAmount string `json:"dst_amount"`
+	// Value is the amount of wei to attach as msg.value when src is the native token.
+	Value       string `json:"value,omitempty"`
+	BlockNumber uint64 `json:"block_number"`
+	Cached      bool   `json:"cached"`
+	// TWAPDeviationBps is the spot price's distance from the guard's TWAP.
+	TWAPDeviationBps *uint64       `json:"twap_deviation_bps,omitempty"`
+	Warnings         []string      `json:"warnings,omitempty"`
+	Quorum           *QuorumReport `json:"quorum,omitempty"`
 }
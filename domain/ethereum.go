@@ -4,16 +4,125 @@ import (
 	"math/big"
 )
 
+// NativeTokenAddress is the pseudo-address wallets use for the chain's native currency.
+const NativeTokenAddress = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+
 type PoolReserves struct {
 	Reserve0    *big.Int `json:"reserve0"`
 	Reserve1    *big.Int `json:"reserve1"`
 	Token0      string   `json:"token0"`
 	Token1      string   `json:"token1"`
 	BlockNumber uint64   `json:"block_number"`
+	Cached      bool     `json:"cached"`
+	// Quorum is set when the reserves were confirmed by several providers.
+	Quorum *QuorumReport `json:"quorum,omitempty"`
+}
+
+type QuorumVote struct {
+	Provider string `json:"provider"`
+	Reserve0 string `json:"reserve0,omitempty"`
+	Reserve1 string `json:"reserve1,omitempty"`
+	Agrees   bool   `json:"agrees"`
+	Error    string `json:"error,omitempty"`
+}
+
+type QuorumReport struct {
+	BlockNumber uint64       `json:"block_number"`
+	BlockHash   string       `json:"block_hash"`
+	Required    int          `json:"required"`
+	Queried     int          `json:"queried"`
+	Agreed      int          `json:"agreed"`
+	Reserve0    string       `json:"reserve0,omitempty"`
+	Reserve1    string       `json:"reserve1,omitempty"`
+	Votes       []QuorumVote `json:"votes"`
 }
 
 type TokenInfo struct {
 	Address  string `json:"address"`
 	Symbol   string `json:"symbol"`
 	Decimals uint8  `json:"decimals"`
+	Verified bool   `json:"verified"`
+}
+
+// CumulativePrices is a Uniswap V2 pair's oracle state read at a block.
+type CumulativePrices struct {
+	Token0               string   `json:"token0"`
+	Token1               string   `json:"token1"`
+	BlockNumber          uint64   `json:"block_number"`
+	BlockTimestamp       uint64   `json:"block_timestamp"`
+	Price0CumulativeLast *big.Int `json:"price0_cumulative_last"`
+	Price1CumulativeLast *big.Int `json:"price1_cumulative_last"`
+	Reserve0             *big.Int `json:"reserve0"`
+	Reserve1             *big.Int `json:"reserve1"`
+	BlockTimestampLast   uint32   `json:"block_timestamp_last"`
+}
+
+type ProviderStatus struct {
+	Name      string  `json:"name"`
+	Priority  int     `json:"priority"`
+	Weight    int     `json:"weight"`
+	Healthy   bool    `json:"healthy"`
+	LatencyMs int64   `json:"latency_ms"`
+	ErrorRate float64 `json:"error_rate"`
+	Head      uint64  `json:"head"`
+	HeadLag   uint64  `json:"head_lag"`
+	Calls     uint64  `json:"calls"`
+	Failures  uint64  `json:"failures"`
+	Hedges    uint64  `json:"hedges"`
+	// Throttled calls waited for the local rate limiter; Rejected calls
+	// would have waited longer than allowed.
+	Throttled   uint64 `json:"throttled"`
+	Rejected    uint64 `json:"rejected"`
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt string `json:"last_error_at,omitempty"`
+}
+
+// LimiterStats counts one provider's calls held back by the local rate limiter.
+type LimiterStats struct {
+	Provider  string
+	Throttled uint64
+	Rejected  uint64
+}
+
+type StoredPool struct {
+	Address     string   `json:"address"`
+	Token0      string   `json:"token0"`
+	Token1      string   `json:"token1"`
+	Reserve0    *big.Int `json:"reserve0,omitempty"`
+	Reserve1    *big.Int `json:"reserve1,omitempty"`
+	BlockNumber uint64   `json:"block_number,omitempty"`
+}
+
+type ReorgStatus struct {
+	Count     uint64 `json:"count"`
+	LastDepth uint64 `json:"last_depth"`
+	LastBlock uint64 `json:"last_common_ancestor"`
+	LastAt    string `json:"last_at,omitempty"`
+}
+
+type BreakerStatus struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Opened              uint64 `json:"opened"`
+	Rejected            uint64 `json:"rejected"`
+	LastOpenedAt        string `json:"last_opened_at,omitempty"`
+}
+
+type CacheStats struct {
+	Size         int    `json:"size"`
+	Capacity     int    `json:"capacity"`
+	Hits         uint64 `json:"hits"`
+	NegativeHits uint64 `json:"negative_hits"`
+	Misses       uint64 `json:"misses"`
+	Evictions    uint64 `json:"evictions"`
+	Expirations  uint64 `json:"expirations"`
+}
+
+type ChainStatus struct {
+	HeadBlock     uint64                `json:"head_block"`
+	HeadHash      string                `json:"head_hash"`
+	HeadUpdatedAt string                `json:"head_updated_at,omitempty"`
+	Reorgs        ReorgStatus           `json:"reorgs"`
+	Breaker       *BreakerStatus        `json:"breaker,omitempty"`
+	Caches        map[string]CacheStats `json:"caches,omitempty"`
 }
@@ -0,0 +1,13 @@
+package domain
+
+type HealthCheck struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Detail    string `json:"detail,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
@@ -4,8 +4,30 @@ import "context"
 
 type UsecaseInterface interface {
 	Estimate(ctx context.Context, req EstimateRequest) (EstimateResponse, error)
+	WatchEstimate(ctx context.Context, req EstimateRequest) (<-chan EstimateResponse, error)
+	ChainStatus(ctx context.Context) ChainStatus
+	TWAP(ctx context.Context, req TWAPRequest) (TWAPResponse, error)
+	ProviderStatus(ctx context.Context) []ProviderStatus
+	Readiness(ctx context.Context) Readiness
 }
 
 type EthereumServiceInterface interface {
 	GetPoolReserves(ctx context.Context, poolAddress string) (*PoolReserves, error)
+	GetPoolReservesQuorum(ctx context.Context, poolAddress string) (*PoolReserves, error)
+	GetPoolFactory(ctx context.Context, poolAddress string) (string, error)
+	SubscribeHeads(ctx context.Context) <-chan uint64
+	ChainStatus(ctx context.Context) ChainStatus
+	GetCumulativePrices(ctx context.Context, poolAddress string, blockNumber uint64) (*CumulativePrices, error)
+	BlockNumberAt(ctx context.Context, timestamp uint64) (uint64, error)
+	ProviderStatus(ctx context.Context) []ProviderStatus
+	HealthChecks(ctx context.Context) []HealthCheck
+}
+
+type StateStoreInterface interface {
+	LoadTokens() ([]TokenInfo, error)
+	SaveToken(token TokenInfo) error
+	LoadPools() ([]StoredPool, error)
+	SavePools(pools []StoredPool) error
+	DeleteReservesAbove(block uint64) error
+	Close() error
 }
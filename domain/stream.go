@@ -0,0 +1,23 @@
+package domain
+
+const (
+	StreamSubscribe    = "subscribe"
+	StreamUnsubscribe  = "unsubscribe"
+	StreamSubscribed   = "subscribed"
+	StreamUnsubscribed = "unsubscribed"
+	StreamEstimate     = "estimate"
+	StreamError        = "error"
+)
+
+type StreamRequest struct {
+	Type            string `json:"type" validate:"required,oneof=subscribe unsubscribe"`
+	ID              string `json:"id" validate:"required,max=64"`
+	EstimateRequest `validate:"-"`
+}
+
+type StreamMessage struct {
+	Type  string            `json:"type"`
+	ID    string            `json:"id,omitempty"`
+	Data  *EstimateResponse `json:"data,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+type TWAPRequest struct {
+	Pool   string `json:"pool" validate:"required"`
+	Window string `json:"window" validate:"required"`
+}
+
+// TWAPResponse carries time-weighted average prices over [StartBlock, EndBlock].
+// Price0 is token1 per token0 and Price1 is token0 per token1, both in raw
+// token units; the *X112 fields are the UQ112x112 fixed-point values.
+type TWAPResponse struct {
+	Pool            string `json:"pool"`
+	Token0          string `json:"token0"`
+	Token1          string `json:"token1"`
+	WindowSeconds   uint64 `json:"window_seconds"`
+	ElapsedSeconds  uint64 `json:"elapsed_seconds"`
+	StartBlock      uint64 `json:"start_block"`
+	EndBlock        uint64 `json:"end_block"`
+	StartTimestamp  uint64 `json:"start_timestamp"`
+	EndTimestamp    uint64 `json:"end_timestamp"`
+	Price0          string `json:"price0"`
+	Price1          string `json:"price1"`
+	Price0UQ112x112 string `json:"price0_uq112x112"`
+	Price1UQ112x112 string `json:"price1_uq112x112"`
+}
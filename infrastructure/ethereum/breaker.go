@@ -0,0 +1,243 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenTimeout      = 30 * time.Second
+	defaultBreakerHalfOpenCalls    = 1
+)
+
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenMaxCalls int
+}
+
+func defaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: defaultBreakerFailureThreshold,
+		OpenTimeout:      defaultBreakerOpenTimeout,
+		HalfOpenMaxCalls: defaultBreakerHalfOpenCalls,
+	}
+}
+
+// breakerClient stops calling the wrapped client after FailureThreshold
+// consecutive failures. After OpenTimeout a limited number of probe calls are
+// let through; one success closes the breaker again, one failure reopens it.
+type breakerClient struct {
+	next   chainClient
+	config BreakerConfig
+	now    func() time.Time
+
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenInFlight    int
+	opened              uint64
+	rejected            uint64
+}
+
+func newBreakerClient(next chainClient, config BreakerConfig) *breakerClient {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = defaultBreakerFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if config.HalfOpenMaxCalls < 1 {
+		config.HalfOpenMaxCalls = defaultBreakerHalfOpenCalls
+	}
+
+	return &breakerClient{
+		next:   next,
+		config: config,
+		now:    time.Now,
+		state:  breakerClosed,
+	}
+}
+
+// allow admits a call and reports whether it is a half-open probe.
+func (b *breakerClient) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if wait := b.config.OpenTimeout - b.now().Sub(b.openedAt); wait > 0 {
+			b.rejected++
+			return false, &domain.RetryAfterError{Err: domain.ErrCircuitOpen, RetryAfter: wait}
+		}
+		b.state = breakerHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	if b.state == breakerHalfOpen {
+		if b.halfOpenInFlight >= b.config.HalfOpenMaxCalls {
+			b.rejected++
+			return false, &domain.RetryAfterError{Err: domain.ErrCircuitOpen, RetryAfter: time.Second}
+		}
+		b.halfOpenInFlight++
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// record settles a call; only probes free a half-open slot, so calls admitted
+// while closed cannot let extra probes through.
+func (b *breakerClient) record(ctx context.Context, probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe && b.state == breakerHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+
+	// The caller giving up says nothing about the provider.
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	if err == nil || classifyError(err).deterministic() {
+		b.consecutiveFailures = 0
+		b.state = breakerClosed
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.state == breakerHalfOpen || b.consecutiveFailures >= b.config.FailureThreshold {
+		if b.state != breakerOpen {
+			b.opened++
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *breakerClient) do(ctx context.Context, call func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = call()
+	b.record(ctx, probe, err)
+
+	return err
+}
+
+// BreakerStatus reports the circuit breaker, or nil when it is not in use.
+func (e *EthereumService) BreakerStatus() *domain.BreakerStatus {
+	if e.breaker == nil {
+		return nil
+	}
+
+	status := e.breaker.status()
+	return &status
+}
+
+func (b *breakerClient) status() domain.BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == breakerOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		state = breakerHalfOpen
+	}
+
+	status := domain.BreakerStatus{
+		State:               state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Opened:              b.opened,
+		Rejected:            b.rejected,
+	}
+	if !b.openedAt.IsZero() {
+		status.LastOpenedAt = b.openedAt.UTC().Format(time.RFC3339)
+	}
+
+	return status
+}
+
+func (b *breakerClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var result uint64
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.BlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var result *types.Header
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var result *types.Header
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.HeaderByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.ChainID(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	var result *ethereum.SyncProgress
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.SyncProgress(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := b.do(ctx, func() (err error) {
+		result, err = b.next.FilterLogs(ctx, q)
+		return err
+	})
+	return result, err
+}
+
+func (b *breakerClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return b.next.SubscribeNewHead(ctx, ch)
+}
@@ -0,0 +1,103 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestBreakerClient(t *testing.T) {
+	unavailable := errors.New("connection refused: timeout")
+	client := &scriptedClient{fakeClient: newFakeClient()}
+
+	now := time.Unix(1700000000, 0)
+	breaker := newBreakerClient(client, BreakerConfig{FailureThreshold: 3, OpenTimeout: 10 * time.Second, HalfOpenMaxCalls: 1})
+	breaker.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	client.errs = []error{errors.New("execution reverted"), unavailable, unavailable}
+	for i := 0; i < 3; i++ {
+		breaker.BlockNumber(ctx)
+	}
+	if state := breaker.status().State; state != breakerClosed {
+		t.Fatalf("Expected reverts not to count towards opening, got %s", state)
+	}
+
+	client.errs = []error{unavailable}
+	breaker.BlockNumber(ctx)
+	if state := breaker.status().State; state != breakerOpen {
+		t.Fatalf("Expected breaker to open, got %s", state)
+	}
+
+	calls := client.callCount("blockNumber")
+	_, err := breaker.BlockNumber(ctx)
+
+	var retryAfter *domain.RetryAfterError
+	if !errors.As(err, &retryAfter) || !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Fatalf("Expected fast failure with retry hint, got %v", err)
+	}
+	if retryAfter.RetryAfter != 10*time.Second {
+		t.Errorf("Expected retry after 10s, got %s", retryAfter.RetryAfter)
+	}
+	if client.callCount("blockNumber") != calls {
+		t.Errorf("Expected no call while the breaker is open")
+	}
+
+	now = now.Add(11 * time.Second)
+	client.errs = []error{unavailable}
+	breaker.BlockNumber(ctx)
+	if state := breaker.status().State; state != breakerOpen {
+		t.Fatalf("Expected failed probe to reopen the breaker, got %s", state)
+	}
+
+	now = now.Add(11 * time.Second)
+	if _, err := breaker.BlockNumber(ctx); err != nil {
+		t.Fatalf("Unexpected error from probe: %v", err)
+	}
+
+	status := breaker.status()
+	if status.State != breakerClosed || status.Opened != 2 || status.Rejected != 1 {
+		t.Errorf("Unexpected breaker status: %+v", status)
+	}
+}
+
+func TestBreakerHalfOpenProbes(t *testing.T) {
+	unavailable := errors.New("connection refused: timeout")
+
+	now := time.Unix(1700000000, 0)
+	breaker := newBreakerClient(newFakeClient(), BreakerConfig{FailureThreshold: 1, OpenTimeout: 10 * time.Second, HalfOpenMaxCalls: 1})
+	breaker.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	slow, err := breaker.allow()
+	if err != nil || slow {
+		t.Fatalf("Expected a regular call while closed, got probe %v (%v)", slow, err)
+	}
+
+	probe, _ := breaker.allow()
+	breaker.record(ctx, probe, unavailable)
+	now = now.Add(11 * time.Second)
+
+	probe, err = breaker.allow()
+	if err != nil || !probe {
+		t.Fatalf("Expected a half-open probe, got probe %v (%v)", probe, err)
+	}
+
+	// The call admitted while closed finishes without freeing the probe slot.
+	breaker.record(cancelled, slow, context.Canceled)
+	if _, err := breaker.allow(); !errors.Is(err, domain.ErrCircuitOpen) {
+		t.Errorf("Expected a second probe to be rejected, got %v", err)
+	}
+
+	breaker.record(cancelled, probe, context.Canceled)
+	if probe, err := breaker.allow(); err != nil || !probe {
+		t.Errorf("Expected the finished probe to free its slot, got probe %v (%v)", probe, err)
+	}
+}
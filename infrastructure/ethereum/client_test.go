@@ -0,0 +1,231 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type fakePool struct {
+	factory  common.Address
+	token0   common.Address
+	token1   common.Address
+	reserve0 *big.Int
+	reserve1 *big.Int
+}
+
+type fakeClient struct {
+	mu       sync.Mutex
+	abi      abi.ABI
+	head     uint64
+	headTime uint64
+	chainID  int64
+	syncing  *ethereum.SyncProgress
+	pools    map[common.Address]fakePool
+	logs     []types.Log
+	headers  map[common.Hash]*types.Header
+	calls    map[string]int
+}
+
+func newFakeClient() *fakeClient {
+	parsed, err := abi.JSON(strings.NewReader(uniswapV2PairABI))
+	if err != nil {
+		panic(err)
+	}
+
+	return &fakeClient{
+		abi:     parsed,
+		head:    100,
+		chainID: 1,
+		pools:   make(map[common.Address]fakePool),
+		headers: make(map[common.Hash]*types.Header),
+		calls:   make(map[string]int),
+	}
+}
+
+func (f *fakeClient) callCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeClient) setHead(head uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.head = head
+}
+
+func (f *fakeClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	method, err := f.abi.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	f.calls[method.Name]++
+
+	pool, ok := f.pools[*msg.To]
+	if !ok {
+		return nil, fmt.Errorf("execution reverted")
+	}
+
+	switch method.Name {
+	case "factory":
+		return method.Outputs.Pack(pool.factory)
+	case "token0":
+		return method.Outputs.Pack(pool.token0)
+	case "token1":
+		return method.Outputs.Pack(pool.token1)
+	case "getReserves":
+		return method.Outputs.Pack(pool.reserve0, pool.reserve1, uint32(0))
+	default:
+		return nil, fmt.Errorf("unsupported method %s", method.Name)
+	}
+}
+
+func (f *fakeClient) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return f.CallContract(ctx, msg, nil)
+}
+
+func (f *fakeClient) BlockNumber(ctx context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["blockNumber"]++
+	return f.head, nil
+}
+
+func (f *fakeClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["headerByNumber"]++
+	if number != nil {
+		return &types.Header{Number: new(big.Int).Set(number), Time: f.headTime}, nil
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(f.head), Time: f.headTime}, nil
+}
+
+func (f *fakeClient) ChainID(ctx context.Context) (*big.Int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return big.NewInt(f.chainID), nil
+}
+
+func (f *fakeClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.syncing, nil
+}
+
+func (f *fakeClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["headerByHash"]++
+
+	header, ok := f.headers[hash]
+	if !ok {
+		return nil, fmt.Errorf("header %s not found", hash.Hex())
+	}
+	return header, nil
+}
+
+// extendChain builds count linked headers on top of parent and registers them
+// so they can be fetched by hash. A non-zero fork byte yields distinct hashes.
+func (f *fakeClient) extendChain(parent *types.Header, count int, fork byte) []*types.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	headers := make([]*types.Header, 0, count)
+	for i := 0; i < count; i++ {
+		header := &types.Header{
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			ParentHash: parent.Hash(),
+			Extra:      []byte{fork},
+		}
+		f.headers[header.Hash()] = header
+		headers = append(headers, header)
+		parent = header
+	}
+
+	return headers
+}
+
+func (f *fakeClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["filterLogs"]++
+
+	addresses := make(map[common.Address]bool, len(q.Addresses))
+	for _, address := range q.Addresses {
+		addresses[address] = true
+	}
+
+	var logs []types.Log
+	for _, log := range f.logs {
+		if !addresses[log.Address] || log.BlockNumber < q.FromBlock.Uint64() || log.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+func (f *fakeClient) addSyncLog(pool common.Address, block uint64, index uint, reserve0, reserve1 int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.abi.Events["Sync"].Inputs.Pack(big.NewInt(reserve0), big.NewInt(reserve1))
+	if err != nil {
+		panic(err)
+	}
+
+	f.logs = append(f.logs, types.Log{
+		Address:     pool,
+		Topics:      []common.Hash{f.abi.Events["Sync"].ID},
+		Data:        data,
+		BlockNumber: block,
+		Index:       index,
+	})
+}
+
+func (f *fakeClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func genesisHeader(number int64) *types.Header {
+	return &types.Header{Number: big.NewInt(number)}
+}
+
+func newTestService(client chainClient) *EthereumService {
+	service := &EthereumService{
+		client:           client,
+		tokenAddresses:   newLRUCache[common.Address, poolTokens](defaultCacheConfig()),
+		tokenInfoCache:   newLRUCache[common.Address, tokenInfoEntry](defaultCacheConfig()),
+		poolFactories:    newLRUCache[common.Address, common.Address](defaultCacheConfig()),
+		tokenOverrides:   make(map[common.Address]TokenOverride),
+		reserveCache:     newReserveCache(),
+		reserveFetches:   newFlightGroup[*domain.PoolReserves](),
+		poolState:        newPoolState(),
+		chain:            newBlockChain(),
+		headPollInterval: defaultHeadPollInterval,
+		maxBlockAge:      defaultMaxBlockAge,
+		logger:           slog.Default(),
+	}
+
+	if err := service.initABI(); err != nil {
+		panic(err)
+	}
+
+	return service
+}
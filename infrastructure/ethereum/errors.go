@@ -0,0 +1,144 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type errorClass int
+
+const (
+	classUnknown errorClass = iota
+	classTimeout
+	classRateLimited
+	classUnavailable
+	classReverted
+	classInvalidInput
+	classNotFound
+)
+
+func (c errorClass) String() string {
+	switch c {
+	case classTimeout:
+		return "timeout"
+	case classRateLimited:
+		return "rate_limited"
+	case classUnavailable:
+		return "unavailable"
+	case classReverted:
+		return "reverted"
+	case classInvalidInput:
+		return "invalid_input"
+	case classNotFound:
+		return "not_found"
+	default:
+		return "unknown"
+	}
+}
+
+// transient reports whether the same request may succeed if sent again.
+func (c errorClass) transient() bool {
+	return c == classTimeout || c == classRateLimited || c == classUnavailable
+}
+
+// deterministic reports whether every provider would give the same answer.
+func (c errorClass) deterministic() bool {
+	return c == classReverted || c == classInvalidInput || c == classNotFound
+}
+
+const (
+	rpcCodeExecutionReverted = 3
+	rpcCodeInvalidParams     = -32602
+	rpcCodeLimitExceeded     = -32005
+)
+
+func classifyError(err error) errorClass {
+	if err == nil {
+		return classUnknown
+	}
+
+	if errors.Is(err, ethereum.NotFound) {
+		return classNotFound
+	}
+
+	if errors.Is(err, errThrottled) {
+		return classRateLimited
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == 429:
+			return classRateLimited
+		case httpErr.StatusCode == 408 || httpErr.StatusCode == 504:
+			return classTimeout
+		case httpErr.StatusCode >= 500:
+			return classUnavailable
+		}
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case rpcCodeExecutionReverted:
+			return classReverted
+		case rpcCodeInvalidParams:
+			return classInvalidInput
+		case rpcCodeLimitExceeded:
+			return classRateLimited
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return classTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return classTimeout
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return classUnavailable
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "execution reverted"):
+		return classReverted
+	case strings.Contains(message, "invalid argument") || strings.Contains(message, "invalid address"):
+		return classInvalidInput
+	case strings.Contains(message, "rate limit") || strings.Contains(message, "too many requests") ||
+		strings.Contains(message, "compute units"):
+		return classRateLimited
+	case strings.Contains(message, "timeout") || strings.Contains(message, "timed out"):
+		return classTimeout
+	}
+
+	return classUnknown
+}
+
+// classified attaches the domain error matching err's class so callers can
+// map it to a response without inspecting RPC details.
+func classified(err error) error {
+	switch classifyError(err) {
+	case classReverted:
+		return fmt.Errorf("%w: %w", domain.ErrExecutionReverted, err)
+	case classInvalidInput:
+		return fmt.Errorf("%w: %w", domain.ErrInvalidRequest, err)
+	case classTimeout, classRateLimited, classUnavailable:
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	default:
+		return err
+	}
+}
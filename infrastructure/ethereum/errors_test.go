@@ -0,0 +1,55 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type codedError struct {
+	code    int
+	message string
+}
+
+func (e codedError) Error() string  { return e.message }
+func (e codedError) ErrorCode() int { return e.code }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected errorClass
+	}{
+		{name: "Deadline", err: fmt.Errorf("call: %w", context.DeadlineExceeded), expected: classTimeout},
+		{name: "HTTP 429", err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, expected: classRateLimited},
+		{name: "HTTP 502", err: rpc.HTTPError{StatusCode: 502, Status: "502 Bad Gateway"}, expected: classUnavailable},
+		{name: "Limit exceeded", err: codedError{code: -32005, message: "limit exceeded"}, expected: classRateLimited},
+		{name: "Revert code", err: codedError{code: 3, message: "execution reverted: ds-math-sub-underflow"}, expected: classReverted},
+		{name: "Revert message", err: errors.New("execution reverted"), expected: classReverted},
+		{name: "Invalid params", err: codedError{code: -32602, message: "invalid argument 0: hex string has length 3"}, expected: classInvalidInput},
+		{name: "Not found", err: ethereum.NotFound, expected: classNotFound},
+		{name: "Unknown", err: errors.New("something odd"), expected: classUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifyError(tt.err); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestClassifiedWrapsDomainErrors(t *testing.T) {
+	if err := classified(errors.New("execution reverted")); !errors.Is(err, domain.ErrExecutionReverted) || !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Errorf("Expected revert to map to an invalid request, got %v", err)
+	}
+	if err := classified(rpc.HTTPError{StatusCode: 429}); !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected rate limit to map to upstream unavailable, got %v", err)
+	}
+}
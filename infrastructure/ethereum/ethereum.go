@@ -2,27 +2,177 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/DiDinar5/1inch_test_task/domain"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type EthereumService struct {
-	client           *ethclient.Client
+	client           chainClient
+	providers        *providerPool
+	providerConfigs  []ProviderConfig
+	retryPolicy      RetryPolicy
+	breaker          *breakerClient
+	breakerConfig    BreakerConfig
+	hedgeConfig      *HedgeConfig
+	quorum           QuorumConfig
+	callTimeout      time.Duration
+	rateLimit        *RateLimitConfig
+	rpcObserver      RPCObserver
 	uniswapV2ABI     abi.ABI
 	erc20ABI         abi.ABI
-	tokenAddresses   map[string]string
-	tokenAddressesMu sync.RWMutex
-	tokenInfoCache   map[string]*domain.TokenInfo
-	tokenInfoMu      sync.RWMutex
+	tokenAddresses   *lruCache[common.Address, poolTokens]
+	tokenInfoCache   *lruCache[common.Address, tokenInfoEntry]
+	poolFactories    *lruCache[common.Address, common.Address]
+	cacheConfig      CacheConfig
+	tokenOverrides   map[common.Address]TokenOverride
+	reserveCache     *reserveCache
+	reserveFetches   *flightGroup[*domain.PoolReserves]
+	poolState        *poolState
+	chain            *blockChain
+	store            domain.StateStoreInterface
+	poolWriter       *poolWriter
+	workers          sync.WaitGroup
+	restoredPools    []domain.StoredPool
+	restoredPoolsMu  sync.Mutex
+	syncLogs         bool
+	headPollInterval time.Duration
+	head             headState
+	chainID          uint64
+	maxBlockAge      time.Duration
+	logger           *slog.Logger
+}
+
+// poolTokens and tokenInfoEntry remember the head they were read at so they
+// can be dropped if that block is orphaned.
+type poolTokens struct {
+	token0 common.Address
+	token1 common.Address
+	block  uint64
+}
+
+type tokenInfoEntry struct {
+	info  *domain.TokenInfo
+	block uint64
+}
+
+type Option func(*EthereumService)
+
+func WithHeadPollInterval(interval time.Duration) Option {
+	return func(e *EthereumService) {
+		if interval > 0 {
+			e.headPollInterval = interval
+		}
+	}
+}
+
+// WithSyncLogs answers GetPoolReserves for tracked pools from Sync logs
+// applied on every new head instead of calling getReserves.
+func WithSyncLogs(enabled bool) Option {
+	return func(e *EthereumService) {
+		e.syncLogs = enabled
+	}
+}
+
+func WithStateStore(store domain.StateStoreInterface) Option {
+	return func(e *EthereumService) {
+		e.store = store
+	}
+}
+
+// WithRetryPolicy configures retries of transient RPC failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(e *EthereumService) {
+		e.retryPolicy = policy
+	}
+}
+
+func WithCircuitBreaker(config BreakerConfig) Option {
+	return func(e *EthereumService) {
+		e.breakerConfig = config
+	}
+}
+
+// WithHedging races reads that are slower than the configured latency
+// quantile against the next provider.
+func WithHedging(config HedgeConfig) Option {
+	return func(e *EthereumService) {
+		e.hedgeConfig = &config
+	}
+}
+
+// WithQuorum sets how many providers a quorum read asks and how many of
+// them must return identical reserves.
+func WithQuorum(config QuorumConfig) Option {
+	return func(e *EthereumService) {
+		e.quorum = config
+	}
+}
+
+// WithCallTimeout bounds every RPC call made to a single provider.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(e *EthereumService) {
+		if timeout > 0 {
+			e.callTimeout = timeout
+		}
+	}
+}
+
+// WithRateLimit throttles outbound calls to each provider by compute-unit cost.
+func WithRateLimit(config RateLimitConfig) Option {
+	return func(e *EthereumService) {
+		if config.UnitsPerSecond > 0 {
+			e.rateLimit = &config
+		}
+	}
+}
+
+// WithRPCObserver reports every provider call, e.g. to export metrics.
+func WithRPCObserver(observer RPCObserver) Option {
+	return func(e *EthereumService) {
+		e.rpcObserver = observer
+	}
+}
+
+// WithCacheConfig bounds the pool token and token metadata caches.
+func WithCacheConfig(config CacheConfig) Option {
+	return func(e *EthereumService) {
+		e.cacheConfig = config
+	}
+}
+
+// WithLogger sets the logger used for background work, e.g. one tagged with the chain name.
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *EthereumService) {
+		if logger != nil {
+			e.logger = logger
+		}
+	}
+}
+
+// WithProviders replaces the single rpcURL with a pool of providers that are
+// health-checked and failed over between.
+func WithProviders(providers []ProviderConfig) Option {
+	return func(e *EthereumService) {
+		e.providerConfigs = providers
+	}
+}
+
+func WithTokenOverrides(overrides map[common.Address]TokenOverride) Option {
+	return func(e *EthereumService) {
+		e.tokenOverrides = overrides
+	}
 }
 
 const uniswapV2PairABI = `[
@@ -37,6 +187,36 @@ const uniswapV2PairABI = `[
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": false, "internalType": "uint112", "name": "reserve0", "type": "uint112"},
+			{"indexed": false, "internalType": "uint112", "name": "reserve1", "type": "uint112"}
+		],
+		"name": "Sync",
+		"type": "event"
+	},
+	{
+		"inputs": [],
+		"name": "price0CumulativeLast",
+		"outputs": [{"internalType": "uint256", "name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "price1CumulativeLast",
+		"outputs": [{"internalType": "uint256", "name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "factory",
+		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [],
 		"name": "token0",
@@ -70,22 +250,60 @@ const erc20ABI = `[
 	}
 ]`
 
-func NewEthereumService(rpcURL string) (*EthereumService, error) {
-	client, err := ethclient.Dial(rpcURL)
+func NewEthereumService(rpcURL string, opts ...Option) (*EthereumService, error) {
+	service := &EthereumService{
+		cacheConfig:      defaultCacheConfig(),
+		tokenOverrides:   make(map[common.Address]TokenOverride),
+		reserveCache:     newReserveCache(),
+		reserveFetches:   newFlightGroup[*domain.PoolReserves](),
+		poolState:        newPoolState(),
+		chain:            newBlockChain(),
+		headPollInterval: defaultHeadPollInterval,
+		maxBlockAge:      defaultMaxBlockAge,
+		retryPolicy:      defaultRetryPolicy(),
+		callTimeout:      defaultCallTimeout,
+		breakerConfig:    defaultBreakerConfig(),
+		logger:           slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	service.tokenAddresses = newLRUCache[common.Address, poolTokens](service.cacheConfig)
+	service.tokenInfoCache = newLRUCache[common.Address, tokenInfoEntry](service.cacheConfig)
+	service.poolFactories = newLRUCache[common.Address, common.Address](service.cacheConfig)
+
+	if len(service.providerConfigs) == 0 {
+		service.providerConfigs = []ProviderConfig{{Name: "default", URL: rpcURL, Weight: 1}}
+	}
+	service.quorum = resolveQuorum(service.quorum, len(service.providerConfigs))
+
+	providers, err := dialProviders(service.providerConfigs, service.rateLimit, service.logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum: %w", err)
 	}
-
-	service := &EthereumService{
-		client:         client,
-		tokenAddresses: make(map[string]string),
-		tokenInfoCache: make(map[string]*domain.TokenInfo),
+	service.providers = providers
+	providers.callTimeout = service.callTimeout
+	providers.observer = service.rpcObserver
+	if service.hedgeConfig != nil {
+		providers.hedge = newHedger(*service.hedgeConfig)
 	}
+	service.breaker = newBreakerClient(newRetryClient(providers, service.retryPolicy), service.breakerConfig)
+	service.client = service.breaker
 
 	if err := service.initABI(); err != nil {
 		return nil, fmt.Errorf("failed to initialize ABI: %w", err)
 	}
 
+	if service.store != nil {
+		service.poolWriter = newPoolWriter(service.store, service.logger)
+	}
+
+	if err := service.loadState(); err != nil {
+		return nil, fmt.Errorf("failed to load persisted state: %w", err)
+	}
+
 	return service, nil
 }
 
@@ -105,61 +323,64 @@ func (e *EthereumService) initABI() error {
 	return nil
 }
 
-func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
+func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress string) (result *domain.PoolReserves, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.GetPoolReserves", trace.WithAttributes(attribute.String("pool.address", poolAddress)))
+	defer func() {
+		if result != nil {
+			span.SetAttributes(attribute.Int64("block.number", int64(result.BlockNumber)), attribute.Bool("cached", result.Cached))
+		}
+		endSpan(span, err)
+	}()
+
 	if !common.IsHexAddress(poolAddress) {
-		return nil, fmt.Errorf("invalid pool address: %s", poolAddress)
+		return nil, fmt.Errorf("%w: invalid pool address: %s", domain.ErrInvalidRequest, poolAddress)
 	}
 
 	poolContract := common.HexToAddress(poolAddress)
 
-	e.tokenAddressesMu.RLock()
-	cachedAddresses, exists := e.tokenAddresses[poolAddress]
-	e.tokenAddressesMu.RUnlock()
-
-	var token0Address, token1Address common.Address
-	var err error
-
-	if exists {
-		addresses := strings.Split(cachedAddresses, ",")
-		if len(addresses) == 2 {
-			token0Address = common.HexToAddress(addresses[0])
-			token1Address = common.HexToAddress(addresses[1])
+	head, headHash, tracked := e.trackedHeadHash()
+	if tracked && e.syncLogs {
+		if reserves, ok := e.poolState.reserves(poolContract, head); ok {
+			return reserves, nil
+		}
+	}
+	if tracked {
+		if cached, ok := e.reserveCache.get(poolContract, head); ok {
+			return cached, nil
 		}
 	}
 
-	if token0Address == (common.Address{}) || token1Address == (common.Address{}) {
-		boundContract := bind.NewBoundContract(poolContract, e.uniswapV2ABI, e.client, e.client, e.client)
+	key := poolContract.Hex() + "@latest"
+	if tracked {
+		key = fmt.Sprintf("%s@%d/%s", poolContract.Hex(), head, headHash.Hex())
+	}
 
-		var token0Result []interface{}
-		if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &token0Result, "token0"); err != nil {
-			return nil, fmt.Errorf("failed to call token0: %w", err)
-		}
-		if len(token0Result) > 0 {
-			if addr, ok := token0Result[0].(common.Address); ok {
-				token0Address = addr
-			} else {
-				return nil, fmt.Errorf("unexpected token0 result type")
-			}
-		}
+	reserves, err := e.reserveFetches.do(ctx, key, func(ctx context.Context) (*domain.PoolReserves, error) {
+		return e.fetchPoolReserves(ctx, poolAddress, poolContract, head, headHash, tracked)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		var token1Result []interface{}
-		if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &token1Result, "token1"); err != nil {
-			return nil, fmt.Errorf("failed to call token1: %w", err)
-		}
-		if len(token1Result) > 0 {
-			if addr, ok := token1Result[0].(common.Address); ok {
-				token1Address = addr
-			} else {
-				return nil, fmt.Errorf("unexpected token1 result type")
-			}
-		}
+	// Waiters share one result; hand each its own copy.
+	poolReserves := *reserves
+	return &poolReserves, nil
+}
 
-		e.tokenAddressesMu.Lock()
-		e.tokenAddresses[poolAddress] = token0Address.Hex() + "," + token1Address.Hex()
-		e.tokenAddressesMu.Unlock()
+func (e *EthereumService) fetchPoolReserves(ctx context.Context, poolAddress string, poolContract common.Address, head uint64, headHash common.Hash, tracked bool) (*domain.PoolReserves, error) {
+	token0Address, token1Address, err := e.getPoolTokens(ctx, poolContract)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockNumber uint64
+	var callBlock *big.Int
+	if tracked {
+		blockNumber = head
+		callBlock = new(big.Int).SetUint64(head)
 	}
 
-	reservesData, err := e.callContract(ctx, poolContract, e.uniswapV2ABI, "getReserves")
+	reservesData, err := e.callContract(ctx, poolContract, e.uniswapV2ABI, "getReserves", callBlock)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pool reserves: %w", err)
 	}
@@ -174,87 +395,226 @@ func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress strin
 		return nil, fmt.Errorf("failed to unpack reserves data: %w", err)
 	}
 
-	blockNumber, err := e.client.BlockNumber(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block number: %w", err)
+	if !tracked {
+		blockNumber, err = e.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block number: %w", err)
+		}
 	}
 
-	return &domain.PoolReserves{
+	poolReserves := &domain.PoolReserves{
 		Reserve0:    reserves.Reserve0,
 		Reserve1:    reserves.Reserve1,
 		Token0:      token0Address.Hex(),
 		Token1:      token1Address.Hex(),
 		BlockNumber: blockNumber,
-	}, nil
+	}
+
+	// Only reserves read at the tracked head carry an exact block to persist.
+	if tracked {
+		e.reserveCache.put(poolContract, headHash, poolReserves)
+		if e.syncLogs {
+			e.poolState.track(poolContract, poolReserves)
+		}
+		e.persistPools([]domain.StoredPool{{
+			Address:     poolContract.Hex(),
+			Token0:      poolReserves.Token0,
+			Token1:      poolReserves.Token1,
+			Reserve0:    poolReserves.Reserve0,
+			Reserve1:    poolReserves.Reserve1,
+			BlockNumber: poolReserves.BlockNumber,
+		}})
+	}
+
+	return poolReserves, nil
 }
 
-func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string) (*domain.TokenInfo, error) {
-	if !common.IsHexAddress(tokenAddress) {
-		return nil, fmt.Errorf("invalid token address: %s", tokenAddress)
+func (e *EthereumService) getPoolTokens(ctx context.Context, poolContract common.Address) (common.Address, common.Address, error) {
+	if cached, err, exists := e.tokenAddresses.get(poolContract); exists {
+		if err != nil {
+			return common.Address{}, common.Address{}, err
+		}
+		return cached.token0, cached.token1, nil
+	}
+
+	head, _ := e.trackedHead()
+
+	token0Address, err := e.callAddress(ctx, poolContract, "token0")
+	if err != nil {
+		cacheLookupFailure(e.tokenAddresses, poolContract, poolTokens{block: head}, err)
+		return common.Address{}, common.Address{}, err
+	}
+
+	token1Address, err := e.callAddress(ctx, poolContract, "token1")
+	if err != nil {
+		cacheLookupFailure(e.tokenAddresses, poolContract, poolTokens{block: head}, err)
+		return common.Address{}, common.Address{}, err
+	}
+
+	e.tokenAddresses.set(poolContract, poolTokens{token0: token0Address, token1: token1Address, block: head})
+	e.persistPools([]domain.StoredPool{{Address: poolContract.Hex(), Token0: token0Address.Hex(), Token1: token1Address.Hex()}})
+
+	return token0Address, token1Address, nil
+}
+
+// GetPoolFactory returns the factory that deployed the pair, which decides
+// its swap fee. A pair's factory never changes, so it is cached regardless of reorgs.
+func (e *EthereumService) GetPoolFactory(ctx context.Context, poolAddress string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.GetPoolFactory", trace.WithAttributes(attribute.String("pool.address", poolAddress)))
+	defer func() { endSpan(span, err) }()
+
+	if !common.IsHexAddress(poolAddress) {
+		return "", fmt.Errorf("%w: invalid pool address: %s", domain.ErrInvalidRequest, poolAddress)
+	}
+
+	poolContract := common.HexToAddress(poolAddress)
+
+	if cached, err, exists := e.poolFactories.get(poolContract); exists {
+		if err != nil {
+			return "", err
+		}
+		return cached.Hex(), nil
+	}
+
+	factory, err := e.callAddress(ctx, poolContract, "factory")
+	if err != nil {
+		cacheLookupFailure(e.poolFactories, poolContract, common.Address{}, err)
+		return "", err
+	}
+
+	e.poolFactories.set(poolContract, factory)
+
+	return factory.Hex(), nil
+}
+
+func (e *EthereumService) callAddress(ctx context.Context, poolContract common.Address, method string) (common.Address, error) {
+	data, err := e.callContract(ctx, poolContract, e.uniswapV2ABI, method, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	result, err := e.uniswapV2ABI.Unpack(method, data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+
+	if len(result) == 0 {
+		return common.Address{}, fmt.Errorf("empty %s result", method)
 	}
 
-	e.tokenInfoMu.RLock()
-	if cached, exists := e.tokenInfoCache[tokenAddress]; exists {
-		e.tokenInfoMu.RUnlock()
-		return cached, nil
+	addr, ok := result[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("unexpected %s result type", method)
+	}
+
+	return addr, nil
+}
+
+func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string) (_ *domain.TokenInfo, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.GetTokenInfo", trace.WithAttributes(attribute.String("token.address", tokenAddress)))
+	defer func() { endSpan(span, err) }()
+
+	if !common.IsHexAddress(tokenAddress) {
+		return nil, fmt.Errorf("%w: invalid token address: %s", domain.ErrInvalidRequest, tokenAddress)
 	}
-	e.tokenInfoMu.RUnlock()
 
 	tokenContract := common.HexToAddress(tokenAddress)
-	boundContract := bind.NewBoundContract(tokenContract, e.erc20ABI, e.client, e.client, e.client)
-
-	var symbol string
-	var symbolResult []interface{}
-	if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &symbolResult, "symbol"); err != nil {
-		return nil, fmt.Errorf("failed to call symbol: %w", err)
-	}
-	if len(symbolResult) > 0 {
-		if s, ok := symbolResult[0].(string); ok {
-			symbol = s
-		} else {
-			return nil, fmt.Errorf("unexpected symbol result type")
+
+	if cached, err, exists := e.tokenInfoCache.get(tokenContract); exists {
+		if err != nil {
+			return nil, err
 		}
+		return cached.info, nil
+	}
+
+	head, _ := e.trackedHead()
+
+	tokenInfo, err := e.fetchTokenInfo(ctx, tokenContract)
+	if err != nil {
+		cacheLookupFailure(e.tokenInfoCache, tokenContract, tokenInfoEntry{block: head}, err)
+		return nil, err
 	}
 
-	var decimals uint8
-	var decimalsResult []interface{}
-	if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &decimalsResult, "decimals"); err != nil {
-		return nil, fmt.Errorf("failed to call decimals: %w", err)
-	}
-
-	if len(decimalsResult) > 0 {
-		switch v := decimalsResult[0].(type) {
-		case uint8:
-			decimals = v
-		case uint32:
-			decimals = uint8(v)
-		case uint64:
-			decimals = uint8(v)
-		case *big.Int:
-			if v.IsUint64() && v.Uint64() <= 255 {
-				decimals = uint8(v.Uint64())
-			} else {
-				return nil, fmt.Errorf("decimals value too large: %s", v.String())
-			}
-		default:
-			return nil, fmt.Errorf("unexpected decimals result type: %T", v)
+	e.tokenInfoCache.set(tokenContract, tokenInfoEntry{info: tokenInfo, block: head})
+
+	if e.store != nil {
+		if err := e.store.SaveToken(*tokenInfo); err != nil {
+			e.logger.WarnContext(ctx, "failed to persist token", "token", tokenAddress, "error", err)
 		}
 	}
 
+	return tokenInfo, nil
+}
+
+// cacheLookupFailure negatively caches lookups that failed for a reason every
+// retry would hit again, such as a revert from a non-pair address.
+func cacheLookupFailure[V any](cache *lruCache[common.Address, V], key common.Address, value V, err error) {
+	if classifyError(err).deterministic() || errors.Is(err, domain.ErrInvalidRequest) {
+		cache.setNegative(key, value, err)
+	}
+}
+
+func (e *EthereumService) fetchTokenInfo(ctx context.Context, tokenContract common.Address) (*domain.TokenInfo, error) {
+	override, hasOverride := e.tokenOverrides[tokenContract]
+
 	tokenInfo := &domain.TokenInfo{
-		Address:  tokenAddress,
-		Symbol:   symbol,
-		Decimals: decimals,
+		Address:  tokenContract.Hex(),
+		Verified: true,
+	}
+
+	if hasOverride && override.Symbol != "" {
+		tokenInfo.Symbol = override.Symbol
+		tokenInfo.Verified = false
+	} else {
+		symbolData, err := e.callContract(ctx, tokenContract, e.erc20ABI, "symbol", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call symbol: %w", err)
+		}
+
+		symbol, err := decodeSymbol(e.erc20ABI, symbolData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode symbol: %w", err)
+		}
+		tokenInfo.Symbol = symbol
 	}
 
-	e.tokenInfoMu.Lock()
-	e.tokenInfoCache[tokenAddress] = tokenInfo
-	e.tokenInfoMu.Unlock()
+	if hasOverride && override.Decimals != nil {
+		tokenInfo.Decimals = *override.Decimals
+		tokenInfo.Verified = false
+	} else {
+		var decimals uint8
+		decimalsData, err := e.callContract(ctx, tokenContract, e.erc20ABI, "decimals", nil)
+		if err == nil {
+			decimals, err = decodeDecimals(e.erc20ABI, decimalsData)
+		} else if classifyError(err) != classReverted && !errors.Is(err, domain.ErrInvalidRequest) {
+			return nil, fmt.Errorf("failed to call decimals: %w", err)
+		}
+
+		// decimals() is optional in ERC-20; a token without it is priced at the
+		// common default and flagged so the caller knows to add an override.
+		if err != nil {
+			e.logger.WarnContext(ctx, "token decimals unavailable, assuming default; add a token override if wrong",
+				"token", tokenContract.Hex(), "decimals", defaultDecimals, "error", err)
+			decimals = defaultDecimals
+			tokenInfo.Verified = false
+		}
+		tokenInfo.Decimals = decimals
+	}
 
 	return tokenInfo, nil
 }
 
-func (e *EthereumService) callContract(ctx context.Context, contract common.Address, parsedABI abi.ABI, method string) ([]byte, error) {
+func (e *EthereumService) callContract(ctx context.Context, contract common.Address, parsedABI abi.ABI, method string, blockNumber *big.Int) (_ []byte, err error) {
+	attributes := []attribute.KeyValue{
+		attribute.String("contract.address", contract.Hex()),
+		attribute.String("contract.method", method),
+	}
+	if blockNumber != nil {
+		attributes = append(attributes, attribute.Int64("block.number", blockNumber.Int64()))
+	}
+	ctx, span := tracer.Start(ctx, "EthereumService.callContract "+method, trace.WithAttributes(attributes...))
+	defer func() { endSpan(span, err) }()
+
 	data, err := parsedABI.Pack(method)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack method %s: %w", method, err)
@@ -263,13 +623,13 @@ func (e *EthereumService) callContract(ctx context.Context, contract common.Addr
 	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
 		To:   &contract,
 		Data: data,
-	}, nil)
+	}, blockNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call contract method %s: %w", method, err)
 	}
 
 	if len(result) == 0 {
-		return nil, fmt.Errorf("empty result from contract method %s", method)
+		return nil, fmt.Errorf("%w: empty result from contract method %s", domain.ErrInvalidRequest, method)
 	}
 
 	return result, nil
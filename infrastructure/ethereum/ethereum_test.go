@@ -112,12 +112,12 @@ func TestEthereumService_Caching(t *testing.T) {
 		t.Skipf("Skipping test due to Ethereum connection error: %v", err)
 	}
 
-	if len(service.tokenInfoCache) != 0 {
-		t.Errorf("Expected empty cache, got %d items", len(service.tokenInfoCache))
+	if service.tokenInfoCache.len() != 0 {
+		t.Errorf("Expected empty cache, got %d items", service.tokenInfoCache.len())
 	}
 
-	if len(service.tokenAddresses) != 0 {
-		t.Errorf("Expected empty token addresses cache, got %d items", len(service.tokenAddresses))
+	if service.tokenAddresses.len() != 0 {
+		t.Errorf("Expected empty token addresses cache, got %d items", service.tokenAddresses.len())
 	}
 }
 
@@ -0,0 +1,53 @@
+package ethereum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetPoolFactory(t *testing.T) {
+	poolAddress := "0x1234567890abcdef1234567890abcdef12345678"
+	factory := common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+
+	client := newFakeClient()
+	client.pools[common.HexToAddress(poolAddress)] = fakePool{factory: factory}
+
+	service := newTestService(client)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		poolAddress string
+		want        string
+		wantErr     bool
+	}{
+		{name: "known pair", poolAddress: poolAddress, want: factory.Hex()},
+		{name: "cached pair", poolAddress: poolAddress, want: factory.Hex()},
+		{name: "invalid address", poolAddress: "not-an-address", wantErr: true},
+		{name: "not a pair", poolAddress: "0x9999999999999999999999999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.GetPoolFactory(ctx, tt.poolAddress)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got factory %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected factory %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if calls := client.callCount("factory"); calls != 2 {
+		t.Errorf("Expected one factory call per distinct pool, got %d", calls)
+	}
+}
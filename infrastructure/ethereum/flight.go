@@ -0,0 +1,150 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type flight[T any] struct {
+	done    chan struct{}
+	value   T
+	err     error
+	waiters int
+	ctx     *flightContext
+}
+
+// flightGroup coalesces concurrent calls with the same key into one. The
+// shared call is detached from any single caller: a caller that gives up
+// returns immediately, and the call is only cancelled once every waiter left
+// or the latest of their deadlines passed.
+type flightGroup[T any] struct {
+	mu      sync.Mutex
+	flights map[string]*flight[T]
+}
+
+func newFlightGroup[T any]() *flightGroup[T] {
+	return &flightGroup[T]{flights: make(map[string]*flight[T])}
+}
+
+func (g *flightGroup[T]) do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if ok && f.ctx.Err() == nil {
+		f.waiters++
+		f.ctx.extend(ctx)
+	} else {
+		callCtx := newFlightContext(ctx)
+		f = &flight[T]{done: make(chan struct{}), waiters: 1, ctx: callCtx}
+		g.flights[key] = f
+
+		go func() {
+			f.value, f.err = fn(callCtx)
+			callCtx.stop()
+
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+
+			close(f.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.ctx.stop()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// flightContext keeps the values of the caller that started a shared call
+// but not its cancellation. Its deadline is the latest among the waiters, and
+// none at all once a waiter without a deadline joins.
+type flightContext struct {
+	context.Context
+	cancel context.CancelCauseFunc
+
+	mu       sync.Mutex
+	deadline time.Time
+	bounded  bool
+	timer    *time.Timer
+}
+
+func newFlightContext(ctx context.Context) *flightContext {
+	inner, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	c := &flightContext{Context: inner, cancel: cancel, bounded: true}
+	c.extend(ctx)
+	return c
+}
+
+// extend moves the deadline out to ctx's, or drops it if ctx has none.
+func (c *flightContext) extend(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.bounded {
+		return
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		c.bounded = false
+		c.deadline = time.Time{}
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		return
+	}
+	if !deadline.After(c.deadline) {
+		return
+	}
+
+	c.deadline = deadline
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(time.Until(deadline), func() {
+		c.cancel(context.DeadlineExceeded)
+	})
+}
+
+func (c *flightContext) stop() {
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.mu.Unlock()
+
+	c.cancel(context.Canceled)
+}
+
+func (c *flightContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.deadline, c.bounded
+}
+
+func (c *flightContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type gatedClient struct {
+	*fakeClient
+	gate chan struct{}
+}
+
+func (g *gatedClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-g.gate:
+	}
+	return g.fakeClient.CallContract(ctx, msg, blockNumber)
+}
+
+func TestGetPoolReservesCoalescesConcurrentFetches(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+
+	client := &gatedClient{fakeClient: newFakeClient(), gate: make(chan struct{})}
+	client.pools[common.HexToAddress(poolAddress)] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	ctx := context.Background()
+	service.handleHead(ctx, genesisHeader(100))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Mixed-case addresses must share the same flight.
+			address := poolAddress
+			if i%2 == 0 {
+				address = common.HexToAddress(poolAddress).Hex()
+			}
+			reserves, err := service.GetPoolReserves(ctx, address)
+			if err == nil && reserves.Reserve0.Int64() != 1000 {
+				err = errors.New("unexpected reserves")
+			}
+			errs <- err
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(client.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	for _, method := range []string{"token0", "token1", "getReserves"} {
+		if calls := client.callCount(method); calls != 1 {
+			t.Errorf("Expected one %s call, got %d", method, calls)
+		}
+	}
+}
+
+func TestFlightGroupCancellation(t *testing.T) {
+	group := newFlightGroup[int]()
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	fn := func(ctx context.Context) (int, error) {
+		select {
+		case <-release:
+			return 42, nil
+		case <-ctx.Done():
+			close(cancelled)
+			return 0, ctx.Err()
+		}
+	}
+
+	leaving, leave := context.WithCancel(context.Background())
+	results := make(chan error, 2)
+
+	go func() {
+		_, err := group.do(leaving, "key", fn)
+		results <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	go func() {
+		value, err := group.do(context.Background(), "key", fn)
+		if err == nil && value != 42 {
+			err = errors.New("unexpected value")
+		}
+		results <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	leave()
+	if err := <-results; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancelled caller to return immediately, got %v", err)
+	}
+
+	close(release)
+	if err := <-results; err != nil {
+		t.Fatalf("Expected remaining waiter to get the shared result, got %v", err)
+	}
+
+	// With every waiter gone the shared call itself is cancelled.
+	alone, cancel := context.WithCancel(context.Background())
+	release = make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := group.do(alone, "other", fn); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancellation, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the abandoned call to be cancelled")
+	}
+}
+
+func TestFlightGroupDeadline(t *testing.T) {
+	group := newFlightGroup[time.Time]()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (time.Time, error) {
+		close(started)
+		<-release
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return time.Time{}, errors.New("no deadline")
+		}
+		return deadline, nil
+	}
+
+	first, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	second, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	latest, _ := second.Deadline()
+
+	results := make(chan time.Time, 2)
+	go func() {
+		deadline, _ := group.do(first, "key", fn)
+		results <- deadline
+	}()
+	<-started
+
+	go func() {
+		deadline, _ := group.do(second, "key", fn)
+		results <- deadline
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for range 2 {
+		if deadline := <-results; !deadline.Equal(latest) {
+			t.Errorf("Expected the shared call to run until the latest deadline %s, got %s", latest, deadline)
+		}
+	}
+
+	// A waiter without a deadline lifts it.
+	started = make(chan struct{})
+	release = make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		_, err := group.do(first, "other", fn)
+		errs <- err
+	}()
+	<-started
+
+	go func() {
+		group.do(context.Background(), "other", fn)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-errs; err == nil || err.Error() != "no deadline" {
+		t.Errorf("Expected no deadline on the shared call, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultHeadPollInterval = 2 * time.Second
+	maxHeadAge              = time.Minute
+	providerProbeInterval   = 5 * time.Second
+)
+
+type headState struct {
+	mu        sync.RWMutex
+	number    uint64
+	hash      common.Hash
+	updatedAt time.Time
+	listeners map[chan uint64]struct{}
+}
+
+// Start follows new chain heads until ctx is cancelled. It uses eth_subscribe
+// newHeads when the transport supports it and falls back to polling otherwise.
+func (e *EthereumService) Start(ctx context.Context) {
+	if e.providers != nil {
+		e.workers.Add(1)
+		go func() {
+			defer e.workers.Done()
+			e.providers.probe(ctx, providerProbeInterval)
+		}()
+	}
+
+	e.workers.Add(1)
+	go func() {
+		defer e.workers.Done()
+		e.trackHeads(ctx)
+	}()
+}
+
+func (e *EthereumService) trackHeads(ctx context.Context) {
+	for {
+		err := e.subscribeHeads(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			e.pollHeads(ctx)
+			return
+		}
+
+		e.logger.WarnContext(ctx, "newHeads subscription failed, resubscribing", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.headPollInterval):
+		}
+	}
+}
+
+func (e *EthereumService) subscribeHeads(ctx context.Context) error {
+	headers := make(chan *types.Header, 16)
+
+	sub, err := e.client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case header := <-headers:
+			e.handleHead(ctx, header)
+		}
+	}
+}
+
+func (e *EthereumService) pollHeads(ctx context.Context) {
+	ticker := time.NewTicker(e.headPollInterval)
+	defer ticker.Stop()
+
+	for {
+		header, err := e.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			e.logger.WarnContext(ctx, "failed to poll latest header", "error", err)
+		} else {
+			e.handleHead(ctx, header)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *EthereumService) handleHead(ctx context.Context, header *types.Header) {
+	if header == nil || header.Number == nil {
+		return
+	}
+
+	number := header.Number.Uint64()
+	hash := header.Hash()
+
+	// Any other head, even one already in the window, moves the tip and may
+	// orphan the blocks above it.
+	if !e.chain.empty() && e.chain.tipHash() == hash {
+		e.head.mu.Lock()
+		if e.head.hash == hash {
+			e.head.updatedAt = time.Now()
+		}
+		e.head.mu.Unlock()
+		return
+	}
+
+	detected, err := e.observeHead(ctx, header)
+	if err != nil {
+		e.logger.WarnContext(ctx, "failed to link block to the known chain, resetting state", "block", number, "error", err)
+		e.chain.reset(header)
+		e.poolState.reset(number)
+	}
+	if detected != nil {
+		e.handleReorg(detected)
+	}
+
+	// Reserves cached for the previous head must be gone before readers can
+	// see the new number.
+	e.reserveCache.invalidate(number, hash)
+
+	e.head.mu.Lock()
+	e.head.number = number
+	e.head.hash = hash
+	e.head.updatedAt = time.Now()
+	e.head.mu.Unlock()
+
+	if e.syncLogs {
+		if err := e.syncPoolLogs(ctx, number); err != nil {
+			e.logger.WarnContext(ctx, "failed to sync pool state", "block", number, "error", err)
+		}
+	}
+
+	e.notifyHead(number)
+}
+
+// SubscribeHeads delivers new head numbers until ctx is cancelled. Slow
+// receivers only see the most recent head.
+func (e *EthereumService) SubscribeHeads(ctx context.Context) <-chan uint64 {
+	ch := make(chan uint64, 1)
+
+	e.head.mu.Lock()
+	if e.head.listeners == nil {
+		e.head.listeners = make(map[chan uint64]struct{})
+	}
+	e.head.listeners[ch] = struct{}{}
+	e.head.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		e.head.mu.Lock()
+		delete(e.head.listeners, ch)
+		e.head.mu.Unlock()
+	}()
+
+	return ch
+}
+
+func (e *EthereumService) notifyHead(number uint64) {
+	e.head.mu.RLock()
+	defer e.head.mu.RUnlock()
+
+	for ch := range e.head.listeners {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- number:
+		default:
+		}
+	}
+}
+
+// trackedHead returns the latest head seen by the tracker, if it is fresh enough to trust.
+func (e *EthereumService) trackedHead() (uint64, bool) {
+	number, _, ok := e.trackedHeadHash()
+	return number, ok
+}
+
+func (e *EthereumService) trackedHeadHash() (uint64, common.Hash, bool) {
+	e.head.mu.RLock()
+	defer e.head.mu.RUnlock()
+
+	if e.head.number == 0 || time.Since(e.head.updatedAt) > maxHeadAge {
+		return 0, common.Hash{}, false
+	}
+
+	return e.head.number, e.head.hash, true
+}
@@ -0,0 +1,104 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+const defaultMaxBlockAge = 2 * time.Minute
+
+// WithChainID makes readiness fail when the RPC serves a different chain.
+func WithChainID(chainID uint64) Option {
+	return func(e *EthereumService) {
+		e.chainID = chainID
+	}
+}
+
+// WithMaxBlockAge sets how old the latest block may be before the node is
+// considered stale.
+func WithMaxBlockAge(age time.Duration) Option {
+	return func(e *EthereumService) {
+		if age > 0 {
+			e.maxBlockAge = age
+		}
+	}
+}
+
+// VerifyChainID asks every provider which chain it serves and fails if any
+// of them disagrees with the configured chain ID, so a misplaced URL is
+// caught at startup instead of quoting from the wrong network.
+func (e *EthereumService) VerifyChainID(ctx context.Context) error {
+	if e.chainID == 0 {
+		return nil
+	}
+
+	for _, p := range e.providers.providers {
+		chainID, err := p.client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID from provider %s: %w", p.name, err)
+		}
+		if chainID.Uint64() != e.chainID {
+			return fmt.Errorf("provider %s serves chain %s, expected %d", p.name, chainID, e.chainID)
+		}
+	}
+
+	return nil
+}
+
+// HealthChecks verifies that the RPC is reachable, serves the configured
+// chain, has a fresh head and is not syncing.
+func (e *EthereumService) HealthChecks(ctx context.Context) []domain.HealthCheck {
+	checks := make([]domain.HealthCheck, 0, 4)
+
+	start := time.Now()
+	header, err := e.client.HeaderByNumber(ctx, nil)
+	rpcCheck := domain.HealthCheck{Name: "rpc", Healthy: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		rpcCheck.Detail = err.Error()
+	} else {
+		rpcCheck.Detail = fmt.Sprintf("head %d", header.Number.Uint64())
+	}
+	checks = append(checks, rpcCheck)
+
+	headCheck := domain.HealthCheck{Name: "head_fresh"}
+	if header != nil {
+		age := time.Since(time.Unix(int64(header.Time), 0))
+		headCheck.Healthy = age <= e.maxBlockAge
+		headCheck.Detail = fmt.Sprintf("head %d is %s old, limit %s", header.Number.Uint64(), age.Truncate(time.Second), e.maxBlockAge)
+	} else {
+		headCheck.Detail = "head unknown: RPC unreachable"
+	}
+	checks = append(checks, headCheck)
+
+	start = time.Now()
+	chainID, err := e.client.ChainID(ctx)
+	chainCheck := domain.HealthCheck{Name: "chain_id", LatencyMs: time.Since(start).Milliseconds()}
+	switch {
+	case err != nil:
+		chainCheck.Detail = err.Error()
+	case e.chainID != 0 && chainID.Uint64() != e.chainID:
+		chainCheck.Detail = fmt.Sprintf("RPC serves chain %s, expected %d", chainID, e.chainID)
+	default:
+		chainCheck.Healthy = true
+		chainCheck.Detail = fmt.Sprintf("chain %s", chainID)
+	}
+	checks = append(checks, chainCheck)
+
+	start = time.Now()
+	progress, err := e.client.SyncProgress(ctx)
+	syncCheck := domain.HealthCheck{Name: "not_syncing", LatencyMs: time.Since(start).Milliseconds()}
+	switch {
+	case err != nil:
+		syncCheck.Detail = err.Error()
+	case progress != nil && !progress.Done():
+		syncCheck.Detail = fmt.Sprintf("syncing: block %d of %d", progress.CurrentBlock, progress.HighestBlock)
+	default:
+		syncCheck.Healthy = true
+	}
+	checks = append(checks, syncCheck)
+
+	return checks
+}
@@ -0,0 +1,105 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestHealthChecks(t *testing.T) {
+	fresh := uint64(time.Now().Unix())
+
+	tests := []struct {
+		name      string
+		chainID   int64
+		headTime  uint64
+		syncing   *ethereum.SyncProgress
+		unhealthy string
+	}{
+		{name: "Ready", chainID: 1, headTime: fresh},
+		{name: "Wrong chain", chainID: 56, headTime: fresh, unhealthy: "chain_id"},
+		{name: "Stale head", chainID: 1, headTime: fresh - 600, unhealthy: "head_fresh"},
+		{name: "Syncing", chainID: 1, headTime: fresh, syncing: &ethereum.SyncProgress{CurrentBlock: 90, HighestBlock: 100}, unhealthy: "not_syncing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeClient()
+			client.chainID = tt.chainID
+			client.headTime = tt.headTime
+			client.syncing = tt.syncing
+
+			service := newTestService(client)
+			WithChainID(1)(service)
+
+			checks := service.HealthChecks(context.Background())
+			if len(checks) != 4 {
+				t.Fatalf("Expected 4 checks, got %+v", checks)
+			}
+
+			for _, check := range checks {
+				if expected := check.Name != tt.unhealthy; check.Healthy != expected {
+					t.Errorf("Expected %s healthy=%v, got %+v", check.Name, expected, check)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthChecksUnreachableRPC(t *testing.T) {
+	client := &failingClient{fakeClient: newFakeClient(), err: context.DeadlineExceeded}
+	service := newTestService(&headerFailingClient{failingClient: client})
+
+	checks := service.HealthChecks(context.Background())
+	for _, check := range checks[:2] {
+		if check.Healthy || check.Detail == "" {
+			t.Errorf("Expected %s to fail with details, got %+v", check.Name, check)
+		}
+	}
+}
+
+type headerFailingClient struct {
+	*failingClient
+}
+
+func (h *headerFailingClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return nil, h.err
+}
+
+func TestVerifyChainID(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected uint64
+		chainIDs []int64
+		wantErr  bool
+	}{
+		{name: "All providers match", expected: 1, chainIDs: []int64{1, 1}},
+		{name: "One provider on another chain", expected: 1, chainIDs: []int64{1, 56}, wantErr: true},
+		{name: "No chain ID configured", expected: 0, chainIDs: []int64{56}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &providerPool{}
+			for i, chainID := range tt.chainIDs {
+				client := newFakeClient()
+				client.chainID = chainID
+				pool.providers = append(pool.providers, newProvider(ProviderConfig{Name: fmt.Sprintf("provider-%d", i)}, client))
+			}
+
+			service := newTestService(pool)
+			service.providers = pool
+			WithChainID(tt.expected)(service)
+
+			err := service.VerifyChainID(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
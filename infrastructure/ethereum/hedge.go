@@ -0,0 +1,109 @@
+package ethereum
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	hedgeLatencySamples   = 256
+	hedgeRecomputeEvery   = 16
+	hedgeBudgetBurst      = 10
+	defaultHedgeQuantile  = 0.95
+	defaultHedgeMinDelay  = 50 * time.Millisecond
+	defaultHedgeBudgetPct = 5
+)
+
+type HedgeConfig struct {
+	// Quantile of recent successful call latencies after which a hedge is sent.
+	Quantile float64
+	MinDelay time.Duration
+	// BudgetPercent caps hedges as a share of routed calls.
+	BudgetPercent float64
+}
+
+// hedger decides when a read is slow enough to race against another provider.
+type hedger struct {
+	config HedgeConfig
+	budget *hedgeBudget
+
+	mu       sync.Mutex
+	samples  []time.Duration
+	position int
+	pending  int
+	current  time.Duration
+}
+
+func newHedger(config HedgeConfig) *hedger {
+	if config.Quantile <= 0 || config.Quantile >= 1 {
+		config.Quantile = defaultHedgeQuantile
+	}
+	if config.MinDelay <= 0 {
+		config.MinDelay = defaultHedgeMinDelay
+	}
+	if config.BudgetPercent <= 0 {
+		config.BudgetPercent = defaultHedgeBudgetPct
+	}
+
+	return &hedger{
+		config:  config,
+		budget:  &hedgeBudget{ratio: config.BudgetPercent / 100, tokens: hedgeBudgetBurst},
+		samples: make([]time.Duration, 0, hedgeLatencySamples),
+		current: config.MinDelay,
+	}
+}
+
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < hedgeLatencySamples {
+		h.samples = append(h.samples, latency)
+	} else {
+		h.samples[h.position] = latency
+		h.position = (h.position + 1) % hedgeLatencySamples
+	}
+
+	h.pending++
+	if h.pending >= hedgeRecomputeEvery {
+		h.pending = 0
+		sorted := slices.Clone(h.samples)
+		slices.Sort(sorted)
+		h.current = max(sorted[int(float64(len(sorted)-1)*h.config.Quantile)], h.config.MinDelay)
+	}
+}
+
+func (h *hedger) delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.current
+}
+
+// hedgeBudget earns a fraction of a token per routed call and spends a whole
+// token per hedge, so hedges never exceed ratio of traffic beyond the burst.
+type hedgeBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	tokens float64
+}
+
+func (b *hedgeBudget) earn() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, hedgeBudgetBurst)
+}
+
+func (b *hedgeBudget) spend() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,86 @@
+package ethereum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type slowClient struct {
+	*fakeClient
+	delay time.Duration
+}
+
+func (s *slowClient) BlockNumber(ctx context.Context) (uint64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(s.delay):
+	}
+	return s.fakeClient.BlockNumber(ctx)
+}
+
+func TestProviderPoolHedgesSlowReads(t *testing.T) {
+	slow := &slowClient{fakeClient: newFakeClient(), delay: 2 * time.Second}
+	fast := newFakeClient()
+	fast.setHead(101)
+
+	pool := &providerPool{
+		providers: []*provider{
+			newProvider(ProviderConfig{Name: "slow", Priority: 0}, slow),
+			newProvider(ProviderConfig{Name: "fast", Priority: 1}, fast),
+		},
+		hedge: newHedger(HedgeConfig{MinDelay: 10 * time.Millisecond, BudgetPercent: 5}),
+	}
+
+	start := time.Now()
+	head, err := pool.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if head != 101 {
+		t.Errorf("Expected the hedged provider's answer, got %d", head)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected hedge to cut latency, took %s", elapsed)
+	}
+
+	statuses := pool.status()
+	if statuses[1].Hedges != 1 {
+		t.Errorf("Expected one hedge to the second provider, got %+v", statuses[1])
+	}
+	if statuses[0].Failures != 0 {
+		t.Errorf("Expected the cancelled attempt not to count as a failure, got %+v", statuses[0])
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	budget := &hedgeBudget{ratio: 0.05}
+
+	for i := 0; i < 19; i++ {
+		budget.earn()
+	}
+	if budget.spend() {
+		t.Errorf("Expected no hedge before a full token is earned")
+	}
+
+	budget.earn()
+	if !budget.spend() {
+		t.Errorf("Expected a hedge after 20 calls at 5%%")
+	}
+	if budget.spend() {
+		t.Errorf("Expected budget to be exhausted")
+	}
+}
+
+func TestHedgerDelayTracksQuantile(t *testing.T) {
+	hedger := newHedger(HedgeConfig{Quantile: 0.9, MinDelay: time.Millisecond})
+
+	for i := 1; i <= 100; i++ {
+		hedger.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	if delay := hedger.delay(); delay < 80*time.Millisecond || delay > 95*time.Millisecond {
+		t.Errorf("Expected delay near the 90th percentile, got %s", delay)
+	}
+}
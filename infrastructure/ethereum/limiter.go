@@ -0,0 +1,193 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/time/rate"
+)
+
+const defaultRateLimitMaxWait = 500 * time.Millisecond
+
+// errThrottled is returned when a call would have to queue longer than the
+// configured maximum wait. It is classified as rate limited, so the pool fails
+// over to a provider with budget left.
+var errThrottled = errors.New("rate limit exceeded: local RPC budget exhausted")
+
+// RateLimitConfig describes a provider's quota in compute units.
+type RateLimitConfig struct {
+	UnitsPerSecond float64
+	Burst          int
+	MaxWait        time.Duration
+	// Costs maps JSON-RPC method names to compute units; unlisted methods cost 1.
+	Costs map[string]int
+}
+
+// defaultMethodCosts follows the compute-unit pricing common among hosted providers.
+var defaultMethodCosts = map[string]int{
+	"eth_call":             26,
+	"eth_blockNumber":      10,
+	"eth_getBlockByNumber": 16,
+	"eth_getBlockByHash":   16,
+	"eth_getLogs":          75,
+	"eth_subscribe":        10,
+}
+
+type limiterStats struct {
+	throttled atomic.Uint64
+	rejected  atomic.Uint64
+}
+
+// limitedClient spends compute units from a token bucket before each call,
+// queueing for at most MaxWait (bounded by the caller's deadline).
+type limitedClient struct {
+	next    chainClient
+	limiter *rate.Limiter
+	costs   map[string]int
+	maxWait time.Duration
+	stats   limiterStats
+}
+
+func newLimitedClient(next chainClient, config RateLimitConfig) *limitedClient {
+	costs := make(map[string]int, len(defaultMethodCosts)+len(config.Costs))
+	for method, cost := range defaultMethodCosts {
+		costs[method] = cost
+	}
+	for method, cost := range config.Costs {
+		costs[method] = cost
+	}
+
+	burst := config.Burst
+	for _, cost := range costs {
+		burst = max(burst, cost)
+	}
+
+	maxWait := config.MaxWait
+	if maxWait <= 0 {
+		maxWait = defaultRateLimitMaxWait
+	}
+
+	return &limitedClient{
+		next:    next,
+		limiter: rate.NewLimiter(rate.Limit(config.UnitsPerSecond), burst),
+		costs:   costs,
+		maxWait: maxWait,
+	}
+}
+
+func (l *limitedClient) cost(method string) int {
+	if cost, ok := l.costs[method]; ok && cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+func (l *limitedClient) wait(ctx context.Context, method string) error {
+	reservation := l.limiter.ReserveN(time.Now(), l.cost(method))
+	if !reservation.OK() {
+		l.stats.rejected.Add(1)
+		return fmt.Errorf("%w: %s", errThrottled, method)
+	}
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	maxWait := l.maxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = min(maxWait, time.Until(deadline))
+	}
+	if delay > maxWait {
+		reservation.Cancel()
+		l.stats.rejected.Add(1)
+		return fmt.Errorf("%w: %s", errThrottled, method)
+	}
+
+	l.stats.throttled.Add(1)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (l *limitedClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := l.wait(ctx, "eth_call"); err != nil {
+		return nil, err
+	}
+	return l.next.CallContract(ctx, msg, blockNumber)
+}
+
+func (l *limitedClient) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	caller, ok := l.next.(hashCaller)
+	if !ok {
+		return nil, fmt.Errorf("provider cannot call at block hash")
+	}
+	if err := l.wait(ctx, "eth_call"); err != nil {
+		return nil, err
+	}
+	return caller.CallContractAtHash(ctx, msg, blockHash)
+}
+
+func (l *limitedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := l.wait(ctx, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return l.next.BlockNumber(ctx)
+}
+
+func (l *limitedClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := l.wait(ctx, "eth_getBlockByNumber"); err != nil {
+		return nil, err
+	}
+	return l.next.HeaderByNumber(ctx, number)
+}
+
+func (l *limitedClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	if err := l.wait(ctx, "eth_getBlockByHash"); err != nil {
+		return nil, err
+	}
+	return l.next.HeaderByHash(ctx, hash)
+}
+
+func (l *limitedClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if err := l.wait(ctx, "eth_getLogs"); err != nil {
+		return nil, err
+	}
+	return l.next.FilterLogs(ctx, q)
+}
+
+func (l *limitedClient) ChainID(ctx context.Context) (*big.Int, error) {
+	if err := l.wait(ctx, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	return l.next.ChainID(ctx)
+}
+
+func (l *limitedClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	if err := l.wait(ctx, "eth_syncing"); err != nil {
+		return nil, err
+	}
+	return l.next.SyncProgress(ctx)
+}
+
+func (l *limitedClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if err := l.wait(ctx, "eth_subscribe"); err != nil {
+		return nil, err
+	}
+	return l.next.SubscribeNewHead(ctx, ch)
+}
@@ -0,0 +1,72 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+)
+
+func TestLimitedClient(t *testing.T) {
+	client := newFakeClient()
+	limited := newLimitedClient(client, RateLimitConfig{
+		UnitsPerSecond: 100,
+		Burst:          80,
+		MaxWait:        150 * time.Millisecond,
+		Costs:          map[string]int{"eth_blockNumber": 10},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 8; i++ {
+		if _, err := limited.BlockNumber(ctx); err != nil {
+			t.Fatalf("Unexpected error within burst: %v", err)
+		}
+	}
+	if throttled := limited.stats.throttled.Load(); throttled != 0 {
+		t.Errorf("Expected no throttling within burst, got %d", throttled)
+	}
+
+	start := time.Now()
+	if _, err := limited.BlockNumber(ctx); err != nil {
+		t.Fatalf("Expected queued call to succeed: %v", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("Expected call to queue for budget, waited %s", waited)
+	}
+	if throttled := limited.stats.throttled.Load(); throttled != 1 {
+		t.Errorf("Expected one throttled call, got %d", throttled)
+	}
+
+	// eth_call costs 26 units, more than can be earned within the max wait.
+	_, err := limited.CallContract(ctx, ethereum.CallMsg{}, nil)
+	if !errors.Is(err, errThrottled) || classifyError(err) != classRateLimited {
+		t.Fatalf("Expected throttling error, got %v", err)
+	}
+	if rejected := limited.stats.rejected.Load(); rejected != 1 {
+		t.Errorf("Expected one rejected call, got %d", rejected)
+	}
+	if calls := client.callCount("blockNumber"); calls != 9 {
+		t.Errorf("Expected 9 calls to reach the provider, got %d", calls)
+	}
+}
+
+func TestLimiterStats(t *testing.T) {
+	limited := newProvider(ProviderConfig{Name: "limited"}, newFakeClient())
+	limited.limiter = newLimitedClient(newFakeClient(), RateLimitConfig{UnitsPerSecond: 1})
+	limited.limiter.stats.throttled.Add(3)
+	limited.limiter.stats.rejected.Add(2)
+
+	service := newTestService(newFakeClient())
+	service.providers = &providerPool{providers: []*provider{
+		newProvider(ProviderConfig{Name: "unlimited"}, newFakeClient()),
+		limited,
+	}}
+
+	stats := service.LimiterStats()
+	if len(stats) != 1 || stats[0] != (domain.LimiterStats{Provider: "limited", Throttled: 3, Rejected: 2}) {
+		t.Errorf("Expected counters for the limited provider only, got %+v", stats)
+	}
+}
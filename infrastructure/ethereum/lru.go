@@ -0,0 +1,187 @@
+package ethereum
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+const (
+	defaultCacheCapacity    = 10000
+	defaultCacheTTL         = 24 * time.Hour
+	defaultCacheNegativeTTL = time.Minute
+)
+
+type CacheConfig struct {
+	Capacity    int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+func defaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Capacity:    defaultCacheCapacity,
+		TTL:         defaultCacheTTL,
+		NegativeTTL: defaultCacheNegativeTTL,
+	}
+}
+
+// CacheStats reports usage of the pool token, pool factory and token metadata caches.
+func (e *EthereumService) CacheStats() map[string]domain.CacheStats {
+	if e.tokenAddresses == nil || e.tokenInfoCache == nil || e.poolFactories == nil {
+		return nil
+	}
+
+	return map[string]domain.CacheStats{
+		"pool_tokens":    e.tokenAddresses.stats(),
+		"pool_factories": e.poolFactories.stats(),
+		"token_info":     e.tokenInfoCache.stats(),
+	}
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	err       error
+	expiresAt time.Time
+}
+
+// lruCache is a size-bounded cache with per-entry expiry. Failed lookups can
+// be cached for a shorter time so repeated bad requests do not reach the RPC.
+type lruCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	config  CacheConfig
+	order   *list.List
+	entries map[K]*list.Element
+	now     func() time.Time
+
+	hits         uint64
+	negativeHits uint64
+	misses       uint64
+	evictions    uint64
+	expirations  uint64
+}
+
+func newLRUCache[K comparable, V any](config CacheConfig) *lruCache[K, V] {
+	if config.Capacity <= 0 {
+		config.Capacity = defaultCacheCapacity
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultCacheTTL
+	}
+	if config.NegativeTTL <= 0 {
+		config.NegativeTTL = defaultCacheNegativeTTL
+	}
+
+	return &lruCache[K, V]{
+		config:  config,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached value, or the cached error of a failed lookup.
+func (c *lruCache[K, V]) get(key K) (V, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return zero, nil, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V])
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(element)
+		c.expirations++
+		c.misses++
+		return zero, nil, false
+	}
+
+	c.order.MoveToFront(element)
+	if entry.err != nil {
+		c.negativeHits++
+		return zero, entry.err, true
+	}
+	c.hits++
+
+	return entry.value, nil, true
+}
+
+func (c *lruCache[K, V]) set(key K, value V) {
+	c.store(key, value, nil, c.config.TTL)
+}
+
+// setNegative remembers that looking up key failed with err. value carries
+// any bookkeeping the caller needs, such as the block it was observed at.
+func (c *lruCache[K, V]) setNegative(key K, value V, err error) {
+	c.store(key, value, err, c.config.NegativeTTL)
+}
+
+func (c *lruCache[K, V]) store(key K, value V, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry[K, V]{key: key, value: value, err: err, expiresAt: c.now().Add(ttl)}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.config.Capacity {
+		c.removeElement(c.order.Back())
+		c.evictions++
+	}
+}
+
+// removeIf drops every entry matching match.
+func (c *lruCache[K, V]) removeIf(match func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*lruEntry[K, V])
+		if match(entry.key, entry.value) {
+			c.removeElement(element)
+		}
+		element = next
+	}
+}
+
+func (c *lruCache[K, V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry[K, V]).key)
+}
+
+func (c *lruCache[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lruCache[K, V]) stats() domain.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return domain.CacheStats{
+		Size:         c.order.Len(),
+		Capacity:     c.config.Capacity,
+		Hits:         c.hits,
+		NegativeHits: c.negativeHits,
+		Misses:       c.misses,
+		Evictions:    c.evictions,
+		Expirations:  c.expirations,
+	}
+}
@@ -0,0 +1,111 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRUCache[int, string](CacheConfig{Capacity: 2})
+
+	cache.set(1, "one")
+	cache.set(2, "two")
+	cache.get(1)
+	cache.set(3, "three")
+
+	if _, _, ok := cache.get(2); ok {
+		t.Errorf("Expected least recently used entry to be evicted")
+	}
+	if value, _, ok := cache.get(1); !ok || value != "one" {
+		t.Errorf("Expected recently used entry to survive, got %q ok=%v", value, ok)
+	}
+
+	stats := cache.stats()
+	if stats.Size != 2 || stats.Evictions != 1 || stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLRUCacheExpiresEntries(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cache := newLRUCache[int, string](CacheConfig{Capacity: 10, TTL: time.Minute, NegativeTTL: time.Second})
+	cache.now = func() time.Time { return now }
+
+	lookupErr := errors.New("execution reverted")
+	cache.set(1, "one")
+	cache.setNegative(2, "", lookupErr)
+
+	if _, err, ok := cache.get(2); !ok || !errors.Is(err, lookupErr) {
+		t.Errorf("Expected cached lookup error, got %v ok=%v", err, ok)
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, _, ok := cache.get(2); ok {
+		t.Errorf("Expected negative entry to expire after its TTL")
+	}
+	if _, _, ok := cache.get(1); !ok {
+		t.Errorf("Expected positive entry to outlive the negative TTL")
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, ok := cache.get(1); ok {
+		t.Errorf("Expected positive entry to expire after its TTL")
+	}
+
+	stats := cache.stats()
+	if stats.NegativeHits != 1 || stats.Expirations != 2 || stats.Size != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestGetPoolReservesNormalizesCacheKeys(t *testing.T) {
+	poolAddress := "0x1234567890abcdef1234567890abcdef12345678"
+
+	client := newFakeClient()
+	client.pools[common.HexToAddress(poolAddress)] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	ctx := context.Background()
+
+	for _, address := range []string{poolAddress, common.HexToAddress(poolAddress).Hex()} {
+		if _, err := service.GetPoolReserves(ctx, address); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if size := service.tokenAddresses.len(); size != 1 {
+		t.Errorf("Expected one pool token entry, got %d", size)
+	}
+	if calls := client.callCount("token0"); calls != 1 {
+		t.Errorf("Expected pool tokens to be fetched once, got %d", calls)
+	}
+}
+
+func TestGetPoolReservesCachesFailedLookups(t *testing.T) {
+	client := newFakeClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.GetPoolReserves(ctx, "0x9999999999999999999999999999999999999999"); err == nil {
+			t.Fatalf("Expected error for an address that is not a pair")
+		}
+	}
+
+	if calls := client.callCount("token0"); calls != 1 {
+		t.Errorf("Expected the failed lookup to be cached, got %d token0 calls", calls)
+	}
+	if stats := service.tokenAddresses.stats(); stats.NegativeHits != 2 {
+		t.Errorf("Expected 2 negative hits, got %+v", stats)
+	}
+}
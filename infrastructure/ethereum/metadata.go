@@ -0,0 +1,101 @@
+package ethereum
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+type TokenOverride struct {
+	Address  string `yaml:"address"`
+	Symbol   string `yaml:"symbol"`
+	Decimals *uint8 `yaml:"decimals"`
+}
+
+func LoadTokenOverrides(filename string) (map[common.Address]TokenOverride, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token overrides file: %w", err)
+	}
+
+	var entries []TokenOverride
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse token overrides file: %w", err)
+	}
+
+	overrides := make(map[common.Address]TokenOverride, len(entries))
+	for _, entry := range entries {
+		if !common.IsHexAddress(entry.Address) {
+			return nil, fmt.Errorf("invalid token address in overrides: %s", entry.Address)
+		}
+		overrides[common.HexToAddress(entry.Address)] = entry
+	}
+
+	return overrides, nil
+}
+
+// decodeSymbol accepts the standard string return value, the bytes32 variant
+// used by tokens such as MKR and, as a last resort, raw non-ABI returndata.
+func decodeSymbol(parsedABI abi.ABI, data []byte) (string, error) {
+	if values, err := parsedABI.Unpack("symbol", data); err == nil && len(values) > 0 {
+		if s, ok := values[0].(string); ok && s != "" {
+			return s, nil
+		}
+	}
+
+	if s, ok := printableString(data); ok {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("unsupported symbol returndata: 0x%x", data)
+}
+
+// defaultDecimals is assumed for tokens whose decimals() reverts or returns garbage.
+const defaultDecimals = 18
+
+func decodeDecimals(parsedABI abi.ABI, data []byte) (uint8, error) {
+	if values, err := parsedABI.Unpack("decimals", data); err == nil && len(values) > 0 {
+		if d, ok := values[0].(uint8); ok {
+			return d, nil
+		}
+	}
+
+	if len(data) == 0 || len(data) > 32 {
+		return 0, fmt.Errorf("unsupported decimals returndata: 0x%x", data)
+	}
+
+	value := new(big.Int).SetBytes(data)
+	if !value.IsUint64() || value.Uint64() > 255 {
+		return 0, fmt.Errorf("decimals value too large: %s", value.String())
+	}
+
+	return uint8(value.Uint64()), nil
+}
+
+func printableString(data []byte) (string, bool) {
+	trimmed := bytes.TrimRight(data, "\x00")
+	if len(trimmed) == 0 || !utf8.Valid(trimmed) {
+		return "", false
+	}
+
+	s := strings.TrimSpace(string(trimmed))
+	if s == "" {
+		return "", false
+	}
+
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return "", false
+		}
+	}
+
+	return s, true
+}
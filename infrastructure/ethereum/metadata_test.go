@@ -0,0 +1,258 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDecodeSymbol(t *testing.T) {
+	service := &EthereumService{}
+	if err := service.initABI(); err != nil {
+		t.Fatalf("Failed to initialize ABI: %v", err)
+	}
+
+	abiString, err := service.erc20ABI.Methods["symbol"].Outputs.Pack("USDC")
+	if err != nil {
+		t.Fatalf("Failed to pack symbol: %v", err)
+	}
+
+	bytes32Symbol := make([]byte, 32)
+	copy(bytes32Symbol, "MKR")
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "ABI string",
+			data:     abiString,
+			expected: "USDC",
+		},
+		{
+			name:     "bytes32",
+			data:     bytes32Symbol,
+			expected: "MKR",
+		},
+		{
+			name:     "Raw returndata",
+			data:     []byte("DGD"),
+			expected: "DGD",
+		},
+		{
+			name:        "Empty returndata",
+			data:        []byte{},
+			expectError: true,
+		},
+		{
+			name:        "Binary garbage",
+			data:        []byte{0x01, 0x02, 0xff},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeSymbol(service.erc20ABI, tt.data)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDecodeDecimals(t *testing.T) {
+	service := &EthereumService{}
+	if err := service.initABI(); err != nil {
+		t.Fatalf("Failed to initialize ABI: %v", err)
+	}
+
+	uint256Decimals := make([]byte, 32)
+	uint256Decimals[31] = 6
+
+	tooLarge := make([]byte, 32)
+	tooLarge[30] = 1
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expected    uint8
+		expectError bool
+	}{
+		{
+			name:     "ABI uint8",
+			data:     uint256Decimals,
+			expected: 6,
+		},
+		{
+			name:     "Short raw returndata",
+			data:     []byte{18},
+			expected: 18,
+		},
+		{
+			name:        "Too large",
+			data:        tooLarge,
+			expectError: true,
+		},
+		{
+			name:        "Empty returndata",
+			data:        []byte{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeDecimals(service.erc20ABI, tt.data)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLoadTokenOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	content := `
+- address: "0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2"
+  symbol: "MKR"
+- address: "0xE0B7927c4aF23765Cb51314A0E0521A9645F0E2A"
+  decimals: 9
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write overrides file: %v", err)
+	}
+
+	overrides, err := LoadTokenOverrides(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mkr, ok := overrides[common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")]
+	if !ok || mkr.Symbol != "MKR" || mkr.Decimals != nil {
+		t.Errorf("Unexpected MKR override: %+v", mkr)
+	}
+
+	dgd, ok := overrides[common.HexToAddress("0xe0b7927c4af23765cb51314a0e0521a9645f0e2a")]
+	if !ok || dgd.Symbol != "" || dgd.Decimals == nil || *dgd.Decimals != 9 {
+		t.Errorf("Unexpected DGD override: %+v", dgd)
+	}
+
+	if err := os.WriteFile(path, []byte(`- address: "not-an-address"`), 0o600); err != nil {
+		t.Fatalf("Failed to write overrides file: %v", err)
+	}
+
+	if _, err := LoadTokenOverrides(path); err == nil {
+		t.Errorf("Expected error for invalid address but got none")
+	}
+}
+
+// tokenClient answers ERC-20 metadata calls; decimals fails with decimalsErr.
+type tokenClient struct {
+	*fakeClient
+	erc20       abi.ABI
+	decimalsErr error
+}
+
+func (c *tokenClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := c.erc20.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "symbol":
+		return method.Outputs.Pack("ODD")
+	case "decimals":
+		if c.decimalsErr != nil {
+			return nil, c.decimalsErr
+		}
+		return method.Outputs.Pack(uint8(6))
+	}
+	return nil, fmt.Errorf("unsupported method %s", method.Name)
+}
+
+func TestGetTokenInfoDecimalsFallback(t *testing.T) {
+	tests := []struct {
+		name             string
+		decimalsErr      error
+		expectError      bool
+		expectedDecimals uint8
+		expectedVerified bool
+	}{
+		{
+			name:             "Standard token",
+			expectedDecimals: 6,
+			expectedVerified: true,
+		},
+		{
+			name:             "decimals() reverts",
+			decimalsErr:      errors.New("execution reverted"),
+			expectedDecimals: defaultDecimals,
+		},
+		{
+			name:        "Transient failure",
+			decimalsErr: errors.New("503 service unavailable"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &tokenClient{fakeClient: newFakeClient(), decimalsErr: tt.decimalsErr}
+			service := newTestService(client)
+			client.erc20 = service.erc20ABI
+
+			token, err := service.GetTokenInfo(context.Background(), "0xE0B7927c4aF23765Cb51314A0E0521A9645F0E2A")
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token.Decimals != tt.expectedDecimals || token.Verified != tt.expectedVerified {
+				t.Errorf("Expected %d decimals (verified %v), got %+v", tt.expectedDecimals, tt.expectedVerified, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetCumulativePrices reads the pair's price accumulators and reserves at
+// blockNumber, or at the latest block when blockNumber is zero.
+func (e *EthereumService) GetCumulativePrices(ctx context.Context, poolAddress string, blockNumber uint64) (_ *domain.CumulativePrices, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.GetCumulativePrices", trace.WithAttributes(
+		attribute.String("pool.address", poolAddress),
+		attribute.Int64("block.number", int64(blockNumber)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if !common.IsHexAddress(poolAddress) {
+		return nil, fmt.Errorf("%w: invalid pool address: %s", domain.ErrInvalidRequest, poolAddress)
+	}
+
+	poolContract := common.HexToAddress(poolAddress)
+
+	var number *big.Int
+	if blockNumber > 0 {
+		number = new(big.Int).SetUint64(blockNumber)
+	}
+
+	header, err := e.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header: %w", err)
+	}
+	number = header.Number
+
+	token0Address, token1Address, err := e.getPoolTokens(ctx, poolContract)
+	if err != nil {
+		return nil, err
+	}
+
+	price0, err := e.callUint256(ctx, poolContract, "price0CumulativeLast", number)
+	if err != nil {
+		return nil, err
+	}
+
+	price1, err := e.callUint256(ctx, poolContract, "price1CumulativeLast", number)
+	if err != nil {
+		return nil, err
+	}
+
+	reservesData, err := e.callContract(ctx, poolContract, e.uniswapV2ABI, "getReserves", number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool reserves: %w", err)
+	}
+
+	var reserves struct {
+		Reserve0           *big.Int
+		Reserve1           *big.Int
+		BlockTimestampLast uint32
+	}
+
+	if err := e.uniswapV2ABI.UnpackIntoInterface(&reserves, "getReserves", reservesData); err != nil {
+		return nil, fmt.Errorf("failed to unpack reserves data: %w", err)
+	}
+
+	return &domain.CumulativePrices{
+		Token0:               token0Address.Hex(),
+		Token1:               token1Address.Hex(),
+		BlockNumber:          number.Uint64(),
+		BlockTimestamp:       header.Time,
+		Price0CumulativeLast: price0,
+		Price1CumulativeLast: price1,
+		Reserve0:             reserves.Reserve0,
+		Reserve1:             reserves.Reserve1,
+		BlockTimestampLast:   reserves.BlockTimestampLast,
+	}, nil
+}
+
+// BlockNumberAt returns the last block with a timestamp at or before timestamp.
+func (e *EthereumService) BlockNumberAt(ctx context.Context, timestamp uint64) (block uint64, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.BlockNumberAt", trace.WithAttributes(attribute.Int64("block.timestamp", int64(timestamp))))
+	defer func() {
+		span.SetAttributes(attribute.Int64("block.number", int64(block)))
+		endSpan(span, err)
+	}()
+
+	latest, err := e.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if latest.Time <= timestamp {
+		return latest.Number.Uint64(), nil
+	}
+
+	high := latest.Number.Uint64()
+	step := uint64(64)
+	low := uint64(0)
+
+	// Probe backwards with a growing step to bound the search range.
+	for step < high {
+		header, err := e.client.HeaderByNumber(ctx, new(big.Int).SetUint64(high-step))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get header %d: %w", high-step, err)
+		}
+		if header.Time <= timestamp {
+			low = high - step
+			break
+		}
+		high -= step
+		step *= 2
+	}
+
+	for low+1 < high {
+		mid := low + (high-low)/2
+		header, err := e.client.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get header %d: %w", mid, err)
+		}
+		if header.Time <= timestamp {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return low, nil
+}
+
+func (e *EthereumService) callUint256(ctx context.Context, contract common.Address, method string, blockNumber *big.Int) (*big.Int, error) {
+	data, err := e.callContract(ctx, contract, e.uniswapV2ABI, method, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	result, err := e.uniswapV2ABI.Unpack(method, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+
+	value, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s result type", method)
+	}
+
+	return value, nil
+}
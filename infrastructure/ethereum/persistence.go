@@ -0,0 +1,170 @@
+package ethereum
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// loadState warms the token caches from the state store. Stored reserves are
+// kept aside until the first head, when the Sync log follower resumes from them.
+func (e *EthereumService) loadState() error {
+	if e.store == nil {
+		return nil
+	}
+
+	tokens, err := e.store.LoadTokens()
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		info := token
+		e.tokenInfoCache.set(common.HexToAddress(token.Address), tokenInfoEntry{info: &info})
+	}
+
+	pools, err := e.store.LoadPools()
+	if err != nil {
+		return err
+	}
+
+	for _, pool := range pools {
+		e.tokenAddresses.set(common.HexToAddress(pool.Address), poolTokens{
+			token0: common.HexToAddress(pool.Token0),
+			token1: common.HexToAddress(pool.Token1),
+		})
+	}
+
+	if e.syncLogs {
+		e.restoredPoolsMu.Lock()
+		e.restoredPools = pools
+		e.restoredPoolsMu.Unlock()
+	}
+
+	e.logger.Info("loaded state store", "tokens", len(tokens), "pools", len(pools))
+
+	return nil
+}
+
+func (e *EthereumService) takeRestoredPools() []domain.StoredPool {
+	e.restoredPoolsMu.Lock()
+	defer e.restoredPoolsMu.Unlock()
+
+	pools := e.restoredPools
+	e.restoredPools = nil
+
+	return pools
+}
+
+// persistPools writes pool snapshots through to the state store. Only changed
+// reserves reach the store, so a pool costs at most one write per block.
+func (e *EthereumService) persistPools(pools []domain.StoredPool) {
+	if e.poolWriter == nil || len(pools) == 0 {
+		return
+	}
+
+	e.poolWriter.write(pools)
+}
+
+// Close waits for the goroutines of Start, which return once its context is
+// cancelled, and then stops writes to the state store. Writes are synchronous,
+// so nothing is left to flush; it must be called before the store is closed.
+func (e *EthereumService) Close() {
+	e.workers.Wait()
+	if e.poolWriter != nil {
+		e.poolWriter.close()
+	}
+}
+
+// poolWriter writes pool snapshots to the state store as they change. A
+// snapshot whose reserves match the last one written for that pool is skipped;
+// one without reserves only records the pool's token pair.
+type poolWriter struct {
+	store  domain.StateStoreInterface
+	logger *slog.Logger
+
+	// mu orders writes against deleteAbove so a late write cannot restore
+	// reserves a reorg just deleted.
+	mu      sync.Mutex
+	written map[common.Address]domain.StoredPool
+	closed  bool
+}
+
+func newPoolWriter(store domain.StateStoreInterface, logger *slog.Logger) *poolWriter {
+	return &poolWriter{
+		store:   store,
+		logger:  logger,
+		written: make(map[common.Address]domain.StoredPool),
+	}
+}
+
+func (w *poolWriter) write(pools []domain.StoredPool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	changed := make([]domain.StoredPool, 0, len(pools))
+	for _, pool := range pools {
+		if last, ok := w.written[common.HexToAddress(pool.Address)]; ok && sameReserves(last, pool) {
+			continue
+		}
+		changed = append(changed, pool)
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	if err := w.store.SavePools(changed); err != nil {
+		w.logger.Error("failed to persist pools", "pools", len(changed), "error", err)
+		return
+	}
+
+	for _, pool := range changed {
+		if pool.Reserve0 == nil || pool.Reserve1 == nil {
+			continue
+		}
+		if len(w.written) >= maxTrackedPools {
+			clear(w.written)
+		}
+		w.written[common.HexToAddress(pool.Address)] = pool
+	}
+}
+
+func sameReserves(a, b domain.StoredPool) bool {
+	if a.Reserve0 == nil || b.Reserve0 == nil || a.Reserve1 == nil || b.Reserve1 == nil {
+		return false
+	}
+	return a.Reserve0.Cmp(b.Reserve0) == 0 && a.Reserve1.Cmp(b.Reserve1) == 0
+}
+
+// deleteAbove drops persisted reserves read above block.
+func (w *poolWriter) deleteAbove(block uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	for address, pool := range w.written {
+		if pool.BlockNumber > block {
+			delete(w.written, address)
+		}
+	}
+
+	if err := w.store.DeleteReservesAbove(block); err != nil {
+		w.logger.Error("failed to drop persisted reserves", "above_block", block, "error", err)
+	}
+}
+
+func (w *poolWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+}
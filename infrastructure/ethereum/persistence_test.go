@@ -0,0 +1,240 @@
+package ethereum
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type memoryStore struct {
+	mu     sync.Mutex
+	tokens []domain.TokenInfo
+	pools  map[string]domain.StoredPool
+	saves  int
+}
+
+func (m *memoryStore) LoadTokens() ([]domain.TokenInfo, error) {
+	return m.tokens, nil
+}
+
+func (m *memoryStore) SaveToken(token domain.TokenInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memoryStore) LoadPools() ([]domain.StoredPool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pools := make([]domain.StoredPool, 0, len(m.pools))
+	for _, pool := range m.pools {
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+func (m *memoryStore) SavePools(pools []domain.StoredPool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pool := range pools {
+		if existing, ok := m.pools[pool.Address]; ok && pool.Reserve0 == nil {
+			pool.Reserve0, pool.Reserve1, pool.BlockNumber = existing.Reserve0, existing.Reserve1, existing.BlockNumber
+		}
+		m.pools[pool.Address] = pool
+	}
+	m.saves++
+	return nil
+}
+
+func (m *memoryStore) DeleteReservesAbove(block uint64) error {
+	return nil
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
+
+func TestRestoreFromStateStore(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+	pool := common.HexToAddress(poolAddress)
+
+	store := &memoryStore{
+		tokens: []domain.TokenInfo{{Address: "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2", Symbol: "MKR", Decimals: 18}},
+		pools: map[string]domain.StoredPool{
+			poolAddress: {
+				Address:     poolAddress,
+				Token0:      "0x1111111111111111111111111111111111111111",
+				Token1:      "0x2222222222222222222222222222222222222222",
+				Reserve0:    big.NewInt(1000),
+				Reserve1:    big.NewInt(2000),
+				BlockNumber: 98,
+			},
+		},
+	}
+
+	client := newFakeClient()
+	client.addSyncLog(pool, 98, 0, 1, 1)
+	client.addSyncLog(pool, 99, 0, 1100, 1900)
+
+	service := newTestService(client)
+	service.syncLogs = true
+	service.store = store
+	service.poolWriter = newPoolWriter(store, service.logger)
+	if err := service.loadState(); err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+
+	ctx := context.Background()
+
+	token, err := service.GetTokenInfo(ctx, "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	if err != nil || token.Symbol != "MKR" {
+		t.Errorf("Expected token metadata from the store, got %+v (%v)", token, err)
+	}
+
+	service.handleHead(ctx, genesisHeader(100))
+
+	reserves, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 1100 || reserves.BlockNumber != 100 {
+		t.Errorf("Expected stored reserves advanced by Sync logs, got %s at %d", reserves.Reserve0, reserves.BlockNumber)
+	}
+
+	if calls := client.callCount("getReserves") + client.callCount("token0"); calls != 0 {
+		t.Errorf("Expected no pool calls after restoring state, got %d", calls)
+	}
+
+	service.Close()
+
+	persisted := store.pools[poolAddress]
+	if persisted.Reserve0.Int64() != 1100 || persisted.BlockNumber != 100 {
+		t.Errorf("Expected updated reserves to be written through, got %+v", persisted)
+	}
+}
+
+func TestPersistFetchedPools(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+	pool := common.HexToAddress(poolAddress)
+
+	client := newFakeClient()
+	client.pools[pool] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	store := &memoryStore{pools: make(map[string]domain.StoredPool)}
+	service := newTestService(client)
+	service.store = store
+	service.poolWriter = newPoolWriter(store, service.logger)
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	headers := client.extendChain(genesis, 2, 0)
+	service.handleHead(ctx, genesis)
+
+	if _, err := service.GetPoolReserves(ctx, poolAddress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stored := store.pools[pool.Hex()]
+	if stored.Token0 != "0x1111111111111111111111111111111111111111" || stored.Reserve0.Int64() != 1000 || stored.BlockNumber != 100 {
+		t.Fatalf("Expected the pair and its reserves to be written without sync_logs, got %+v", stored)
+	}
+
+	saves := store.saves
+	service.handleHead(ctx, headers[0])
+	if _, err := service.GetPoolReserves(ctx, poolAddress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.saves != saves {
+		t.Errorf("Expected unchanged reserves to skip the store, got %d more writes", store.saves-saves)
+	}
+
+	client.mu.Lock()
+	client.pools[pool] = fakePool{token0: client.pools[pool].token0, token1: client.pools[pool].token1, reserve0: big.NewInt(900), reserve1: big.NewInt(2200)}
+	client.mu.Unlock()
+
+	service.handleHead(ctx, headers[1])
+	if _, err := service.GetPoolReserves(ctx, poolAddress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored := store.pools[pool.Hex()]; stored.Reserve0.Int64() != 900 || stored.BlockNumber != 102 {
+		t.Errorf("Expected changed reserves to be written through, got %+v", stored)
+	}
+
+	service.Close()
+	service.persistPools([]domain.StoredPool{{Address: poolAddress, Reserve0: big.NewInt(1), Reserve1: big.NewInt(1), BlockNumber: 103}})
+	if stored := store.pools[pool.Hex()]; stored.BlockNumber != 102 {
+		t.Errorf("Expected no writes after Close, got %+v", stored)
+	}
+}
+
+func TestPoolWriterSkipsUnchangedReserves(t *testing.T) {
+	store := &memoryStore{pools: make(map[string]domain.StoredPool)}
+	writer := newPoolWriter(store, slog.Default())
+
+	pool := func(address string, reserve0 int64, block uint64) domain.StoredPool {
+		return domain.StoredPool{Address: address, Reserve0: big.NewInt(reserve0), Reserve1: big.NewInt(1), BlockNumber: block}
+	}
+
+	writer.write([]domain.StoredPool{pool("0x01", 100, 10)})
+	writer.write([]domain.StoredPool{pool("0x01", 100, 11), pool("0x02", 5, 11)})
+
+	if store.saves != 2 || store.pools["0x01"].BlockNumber != 10 || store.pools["0x02"].Reserve0.Int64() != 5 {
+		t.Errorf("Expected only changed reserves to be written, got %d writes: %+v", store.saves, store.pools)
+	}
+
+	writer.deleteAbove(10)
+	writer.write([]domain.StoredPool{pool("0x02", 5, 12)})
+	if stored := store.pools["0x02"]; stored.BlockNumber != 12 {
+		t.Errorf("Expected reserves dropped by a reorg to be written again, got %+v", stored)
+	}
+}
+
+type slowHeadClient struct {
+	*fakeClient
+	active atomic.Int32
+}
+
+func (s *slowHeadClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	s.active.Add(1)
+	defer s.active.Add(-1)
+
+	time.Sleep(20 * time.Millisecond)
+	return s.fakeClient.HeaderByNumber(ctx, number)
+}
+
+func TestCloseWaitsForHeadFollower(t *testing.T) {
+	client := &slowHeadClient{fakeClient: newFakeClient()}
+	store := &memoryStore{pools: make(map[string]domain.StoredPool)}
+
+	service := newTestService(client)
+	service.headPollInterval = time.Millisecond
+	service.store = store
+	service.poolWriter = newPoolWriter(store, service.logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	service.Start(ctx)
+	waitForHead(t, service, 100)
+
+	cancel()
+	service.Close()
+
+	if active := client.active.Load(); active != 0 {
+		t.Errorf("Expected Close to wait for the head follower, %d polls still running", active)
+	}
+}
@@ -0,0 +1,349 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	maxTrackedPools  = 1024
+	maxReorgDepth    = 64
+	maxSyncLogBlocks = 1000
+)
+
+type reserveSnapshot struct {
+	block    uint64
+	reserve0 *big.Int
+	reserve1 *big.Int
+}
+
+type trackedPool struct {
+	token0   common.Address
+	token1   common.Address
+	seededAt uint64
+	history  []reserveSnapshot
+}
+
+// poolState keeps pool reserves in memory, seeded once from getReserves and
+// then advanced by applying Sync logs in (block, log index) order. Recent
+// snapshots are kept so the state can be rolled back when blocks are orphaned.
+type poolState struct {
+	mu          sync.RWMutex
+	syncedBlock uint64
+	pools       map[common.Address]*trackedPool
+}
+
+func newPoolState() *poolState {
+	return &poolState{
+		pools: make(map[common.Address]*trackedPool),
+	}
+}
+
+func (s *poolState) reserves(pool common.Address, head uint64) (*domain.PoolReserves, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.syncedBlock != head {
+		return nil, false
+	}
+
+	tracked, ok := s.pools[pool]
+	if !ok || len(tracked.history) == 0 {
+		return nil, false
+	}
+
+	latest := tracked.history[len(tracked.history)-1]
+
+	return &domain.PoolReserves{
+		Reserve0:    latest.reserve0,
+		Reserve1:    latest.reserve1,
+		Token0:      tracked.token0.Hex(),
+		Token1:      tracked.token1.Hex(),
+		BlockNumber: head,
+		Cached:      true,
+	}, true
+}
+
+// track starts following a pool from reserves read at block. The seed is only
+// accepted when it matches the block the log follower has reached, otherwise
+// events between the two blocks would be missed.
+func (s *poolState) track(pool common.Address, reserves *domain.PoolReserves) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reserves.BlockNumber != s.syncedBlock {
+		return false
+	}
+	if _, exists := s.pools[pool]; exists {
+		return true
+	}
+	if len(s.pools) >= maxTrackedPools {
+		return false
+	}
+
+	s.pools[pool] = &trackedPool{
+		token0:   common.HexToAddress(reserves.Token0),
+		token1:   common.HexToAddress(reserves.Token1),
+		seededAt: reserves.BlockNumber,
+		history: []reserveSnapshot{{
+			block:    reserves.BlockNumber,
+			reserve0: reserves.Reserve0,
+			reserve1: reserves.Reserve1,
+		}},
+	}
+
+	return true
+}
+
+// restore follows pools persisted by a previous run. Logs are replayed from
+// the oldest stored block, skipping events each pool had already seen.
+func (s *poolState) restore(pools []domain.StoredPool, head uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.pools)
+	s.syncedBlock = head
+
+	for _, pool := range pools {
+		if len(s.pools) >= maxTrackedPools {
+			break
+		}
+		if pool.Reserve0 == nil || pool.Reserve1 == nil || pool.BlockNumber == 0 ||
+			pool.BlockNumber > head || head-pool.BlockNumber > maxSyncLogBlocks {
+			continue
+		}
+
+		s.pools[common.HexToAddress(pool.Address)] = &trackedPool{
+			token0:   common.HexToAddress(pool.Token0),
+			token1:   common.HexToAddress(pool.Token1),
+			seededAt: pool.BlockNumber,
+			history: []reserveSnapshot{{
+				block:    pool.BlockNumber,
+				reserve0: pool.Reserve0,
+				reserve1: pool.Reserve1,
+			}},
+		}
+		if pool.BlockNumber < s.syncedBlock {
+			s.syncedBlock = pool.BlockNumber
+		}
+	}
+}
+
+func (s *poolState) addresses() []common.Address {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	addresses := make([]common.Address, 0, len(s.pools))
+	for address := range s.pools {
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+func (s *poolState) synced() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.syncedBlock
+}
+
+type syncEvent struct {
+	pool     common.Address
+	block    uint64
+	index    uint
+	reserve0 *big.Int
+	reserve1 *big.Int
+}
+
+// apply advances the state to block to and returns the pools whose reserves
+// changed. Only pools in followed had their logs fetched; pools seeded while
+// the fetch was in flight are dropped and reseeded.
+func (s *poolState) apply(to uint64, followed []common.Address, events []syncEvent) []domain.StoredPool {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].block != events[j].block {
+			return events[i].block < events[j].block
+		}
+		return events[i].index < events[j].index
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	isFollowed := make(map[common.Address]bool, len(followed))
+	for _, address := range followed {
+		isFollowed[address] = true
+	}
+	for address := range s.pools {
+		if !isFollowed[address] {
+			delete(s.pools, address)
+		}
+	}
+
+	changed := make(map[common.Address]*trackedPool)
+	for _, event := range events {
+		tracked, ok := s.pools[event.pool]
+		if !ok || event.block <= s.syncedBlock || event.block <= tracked.seededAt {
+			continue
+		}
+		changed[event.pool] = tracked
+
+		snapshot := reserveSnapshot{block: event.block, reserve0: event.reserve0, reserve1: event.reserve1}
+		if latest := tracked.history[len(tracked.history)-1]; latest.block == event.block {
+			tracked.history[len(tracked.history)-1] = snapshot
+		} else {
+			tracked.history = append(tracked.history, snapshot)
+		}
+	}
+
+	s.syncedBlock = to
+	s.pruneLocked()
+
+	updated := make([]domain.StoredPool, 0, len(changed))
+	for address, tracked := range changed {
+		latest := tracked.history[len(tracked.history)-1]
+		updated = append(updated, domain.StoredPool{
+			Address:     address.Hex(),
+			Token0:      tracked.token0.Hex(),
+			Token1:      tracked.token1.Hex(),
+			Reserve0:    latest.reserve0,
+			Reserve1:    latest.reserve1,
+			BlockNumber: to,
+		})
+	}
+
+	return updated
+}
+
+// rollback discards every snapshot above block. Pools with no remaining
+// history are dropped and will be reseeded on the next request.
+func (s *poolState) rollback(block uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for address, tracked := range s.pools {
+		keep := len(tracked.history)
+		for keep > 0 && tracked.history[keep-1].block > block {
+			keep--
+		}
+
+		if keep == 0 {
+			delete(s.pools, address)
+			continue
+		}
+		tracked.history = tracked.history[:keep]
+	}
+
+	if s.syncedBlock > block {
+		s.syncedBlock = block
+	}
+}
+
+func (s *poolState) reset(block uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.pools)
+	s.syncedBlock = block
+}
+
+func (s *poolState) pruneLocked() {
+	if s.syncedBlock <= maxReorgDepth {
+		return
+	}
+	floor := s.syncedBlock - maxReorgDepth
+
+	for _, tracked := range s.pools {
+		drop := 0
+		for drop < len(tracked.history)-1 && tracked.history[drop+1].block <= floor {
+			drop++
+		}
+		if drop > 0 {
+			tracked.history = append(tracked.history[:0], tracked.history[drop:]...)
+		}
+	}
+}
+
+// syncPoolLogs brings the tracked pools up to head by fetching Sync logs for
+// the blocks the follower has not seen yet.
+func (e *EthereumService) syncPoolLogs(ctx context.Context, head uint64) error {
+	synced := e.poolState.synced()
+
+	if synced == 0 {
+		e.poolState.restore(e.takeRestoredPools(), head)
+		if synced = e.poolState.synced(); synced == head {
+			return nil
+		}
+	}
+
+	if head > synced+maxSyncLogBlocks {
+		e.poolState.reset(head)
+		return nil
+	}
+
+	if head <= synced {
+		// The tip was replaced: drop state from the orphaned blocks and replay.
+		e.poolState.rollback(head - 1)
+		synced = head - 1
+	}
+
+	addresses := e.poolState.addresses()
+	if len(addresses) == 0 {
+		e.poolState.apply(head, addresses, nil)
+		return nil
+	}
+
+	logs, err := e.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(synced + 1),
+		ToBlock:   new(big.Int).SetUint64(head),
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{e.uniswapV2ABI.Events["Sync"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch Sync logs: %w", err)
+	}
+
+	events := make([]syncEvent, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		event, err := e.decodeSyncLog(log)
+		if err != nil {
+			return fmt.Errorf("failed to decode Sync log in tx %s: %w", log.TxHash.Hex(), err)
+		}
+		events = append(events, event)
+	}
+
+	e.persistPools(e.poolState.apply(head, addresses, events))
+
+	return nil
+}
+
+func (e *EthereumService) decodeSyncLog(log types.Log) (syncEvent, error) {
+	var event struct {
+		Reserve0 *big.Int
+		Reserve1 *big.Int
+	}
+
+	if err := e.uniswapV2ABI.UnpackIntoInterface(&event, "Sync", log.Data); err != nil {
+		return syncEvent{}, err
+	}
+
+	return syncEvent{
+		pool:     log.Address,
+		block:    log.BlockNumber,
+		index:    log.Index,
+		reserve0: event.Reserve0,
+		reserve1: event.Reserve1,
+	}, nil
+}
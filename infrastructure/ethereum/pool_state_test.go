@@ -0,0 +1,126 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetPoolReservesFromSyncLogs(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+	pool := common.HexToAddress(poolAddress)
+
+	client := newFakeClient()
+	client.pools[pool] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	service.syncLogs = true
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	headers := client.extendChain(genesis, 3, 0)
+	service.handleHead(ctx, genesis)
+
+	seeded, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if seeded.Reserve0.Int64() != 1000 || seeded.BlockNumber != 100 {
+		t.Errorf("Unexpected seeded reserves: %+v", seeded)
+	}
+
+	client.addSyncLog(pool, 101, 7, 1500, 1400)
+	client.addSyncLog(pool, 101, 3, 1100, 1900)
+	client.addSyncLog(pool, 102, 0, 1200, 1800)
+
+	service.handleHead(ctx, headers[0])
+
+	reserves, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 1500 || reserves.Reserve1.Int64() != 1400 || reserves.BlockNumber != 101 {
+		t.Errorf("Expected last Sync of block 101 to win, got %s/%s at %d", reserves.Reserve0, reserves.Reserve1, reserves.BlockNumber)
+	}
+
+	service.handleHead(ctx, headers[2])
+
+	reserves, err = service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 1200 || reserves.BlockNumber != 103 {
+		t.Errorf("Expected reserves from block 102 at head 103, got %s at %d", reserves.Reserve0, reserves.BlockNumber)
+	}
+
+	if calls := client.callCount("getReserves"); calls != 1 {
+		t.Errorf("Expected a single getReserves call for seeding, got %d", calls)
+	}
+}
+
+func TestPoolStateRollback(t *testing.T) {
+	pool := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	state := newPoolState()
+	state.reset(100)
+
+	state.track(pool, seededReserves(100, 1000, 2000))
+	state.apply(101, []common.Address{pool}, []syncEvent{
+		{pool: pool, block: 101, reserve0: big.NewInt(1100), reserve1: big.NewInt(1900)},
+	})
+	state.apply(102, []common.Address{pool}, []syncEvent{
+		{pool: pool, block: 102, reserve0: big.NewInt(1200), reserve1: big.NewInt(1800)},
+	})
+
+	state.rollback(101)
+
+	reserves, ok := state.reserves(pool, 101)
+	if !ok {
+		t.Fatalf("Expected reserves after rollback")
+	}
+	if reserves.Reserve0.Int64() != 1100 {
+		t.Errorf("Expected reserves of block 101 after rollback, got %s", reserves.Reserve0)
+	}
+
+	state.rollback(99)
+	if _, ok := state.reserves(pool, 99); ok {
+		t.Errorf("Expected pool seeded above the rollback point to be dropped")
+	}
+}
+
+func TestPoolStateDropsPoolsSeededDuringFetch(t *testing.T) {
+	followed := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	late := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	state := newPoolState()
+	state.reset(100)
+	state.track(followed, seededReserves(100, 1000, 2000))
+
+	addresses := state.addresses()
+	state.track(late, seededReserves(100, 10, 20))
+	state.apply(101, addresses, nil)
+
+	if _, ok := state.reserves(followed, 101); !ok {
+		t.Errorf("Expected followed pool to remain tracked")
+	}
+	if _, ok := state.reserves(late, 101); ok {
+		t.Errorf("Expected pool seeded during the log fetch to be dropped")
+	}
+}
+
+func seededReserves(block uint64, reserve0, reserve1 int64) *domain.PoolReserves {
+	return &domain.PoolReserves{
+		Reserve0:    big.NewInt(reserve0),
+		Reserve1:    big.NewInt(reserve1),
+		Token0:      "0x1111111111111111111111111111111111111111",
+		Token1:      "0x2222222222222222222222222222222222222222",
+		BlockNumber: block,
+	}
+}
@@ -0,0 +1,507 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	latencyAlpha        = 0.3
+	errorRateAlpha      = 0.1
+	maxProviderErrRate  = 0.5
+	maxProviderHeadLag  = 3
+	defaultProbeTimeout = 5 * time.Second
+	defaultCallTimeout  = 5 * time.Second
+)
+
+type ProviderConfig struct {
+	Name     string
+	URL      string
+	Priority int
+	Weight   int
+}
+
+// provider is a single RPC endpoint with rolling health statistics.
+type provider struct {
+	name     string
+	priority int
+	weight   int
+	client   chainClient
+	limiter  *limitedClient
+
+	mu          sync.RWMutex
+	latency     time.Duration
+	errorRate   float64
+	head        uint64
+	calls       uint64
+	failures    uint64
+	hedges      uint64
+	lastError   string
+	lastErrorAt time.Time
+}
+
+func (p *provider) observe(latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.latency == 0 {
+		p.latency = latency
+	} else {
+		p.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(p.latency))
+	}
+
+	failed := 0.0
+	if err != nil {
+		failed = 1
+		p.failures++
+		p.lastError = err.Error()
+		p.lastErrorAt = time.Now()
+	}
+	p.errorRate = errorRateAlpha*failed + (1-errorRateAlpha)*p.errorRate
+}
+
+func (p *provider) countHedge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hedges++
+}
+
+func (p *provider) observeHead(head uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if head > p.head {
+		p.head = head
+	}
+}
+
+// providerPool routes calls to the healthiest provider and fails over to the
+// next one on errors that another endpoint might not return.
+type providerPool struct {
+	providers   []*provider
+	hedge       *hedger
+	callTimeout time.Duration
+	observer    RPCObserver
+}
+
+// RPCObserver is told about every call sent to a single provider, including
+// health probes and hedged attempts. outcome is "ok" or an error class.
+type RPCObserver interface {
+	ObserveRPC(method, provider, outcome string, duration time.Duration)
+}
+
+func (p *providerPool) observeCall(method string, provider *provider, duration time.Duration, err error) {
+	if p.observer == nil {
+		return
+	}
+
+	outcome := "ok"
+	if err != nil {
+		outcome = classifyError(err).String()
+	}
+	p.observer.ObserveRPC(method, provider.name, outcome, duration)
+}
+
+// dialProviders connects to every configured provider. With a rate limit each
+// provider gets its own bucket, since quotas are per provider account.
+func dialProviders(configs []ProviderConfig, rateLimit *RateLimitConfig, logger *slog.Logger) (*providerPool, error) {
+	pool := &providerPool{}
+
+	for _, config := range configs {
+		client, err := ethclient.Dial(config.URL)
+		if err != nil {
+			logger.Warn("failed to connect to provider", "provider", config.Name, "error", err)
+			continue
+		}
+
+		var limiter *limitedClient
+		var next chainClient = client
+		if rateLimit != nil {
+			limiter = newLimitedClient(client, *rateLimit)
+			next = limiter
+		}
+
+		provider := newProvider(config, next)
+		provider.limiter = limiter
+		pool.providers = append(pool.providers, provider)
+	}
+
+	if len(pool.providers) == 0 {
+		return nil, fmt.Errorf("no RPC provider could be reached")
+	}
+
+	return pool, nil
+}
+
+func newProvider(config ProviderConfig, client chainClient) *provider {
+	weight := config.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+
+	return &provider{
+		name:     config.Name,
+		priority: config.Priority,
+		weight:   weight,
+		client:   client,
+	}
+}
+
+// probe refreshes every provider's head and latency until ctx is cancelled.
+func (p *providerPool) probe(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var wg sync.WaitGroup
+		for _, provider := range p.providers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				probeCtx, cancel := context.WithTimeout(ctx, defaultProbeTimeout)
+				defer cancel()
+
+				start := time.Now()
+				head, err := provider.client.BlockNumber(probeCtx)
+				if ctx.Err() != nil {
+					return
+				}
+				provider.observe(time.Since(start), err)
+				p.observeCall("eth_blockNumber", provider, time.Since(start), err)
+				if err == nil {
+					provider.observeHead(head)
+				}
+			}()
+		}
+		wg.Wait()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *providerPool) bestHead() uint64 {
+	var best uint64
+	for _, provider := range p.providers {
+		provider.mu.RLock()
+		if provider.head > best {
+			best = provider.head
+		}
+		provider.mu.RUnlock()
+	}
+	return best
+}
+
+type providerRank struct {
+	provider *provider
+	healthy  bool
+	score    float64
+}
+
+// order returns the providers to try, best first. Healthy providers in the
+// lowest priority tier share traffic according to their weights.
+func (p *providerPool) order() []*provider {
+	best := p.bestHead()
+
+	ranks := make([]providerRank, 0, len(p.providers))
+	for _, provider := range p.providers {
+		provider.mu.RLock()
+		lag := best - provider.head
+		healthy := provider.errorRate < maxProviderErrRate && lag <= maxProviderHeadLag
+		score := float64(provider.latency.Milliseconds())*(1+10*provider.errorRate) + 250*float64(lag)
+		provider.mu.RUnlock()
+
+		ranks = append(ranks, providerRank{provider: provider, healthy: healthy, score: score})
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].healthy != ranks[j].healthy {
+			return ranks[i].healthy
+		}
+		if ranks[i].provider.priority != ranks[j].provider.priority {
+			return ranks[i].provider.priority < ranks[j].provider.priority
+		}
+		return ranks[i].score < ranks[j].score
+	})
+
+	tier := 0
+	for tier < len(ranks) && ranks[tier].healthy && ranks[tier].provider.priority == ranks[0].provider.priority {
+		tier++
+	}
+	if tier > 1 {
+		total := 0
+		for _, rank := range ranks[:tier] {
+			total += rank.provider.weight
+		}
+		pick := rand.IntN(total)
+		for i, rank := range ranks[:tier] {
+			if pick < rank.provider.weight {
+				ranks[0], ranks[i] = ranks[i], ranks[0]
+				break
+			}
+			pick -= rank.provider.weight
+		}
+	}
+
+	ordered := make([]*provider, len(ranks))
+	for i, rank := range ranks {
+		ordered[i] = rank.provider
+	}
+
+	return ordered
+}
+
+type attempt[T any] struct {
+	value    T
+	err      error
+	provider *provider
+}
+
+// route runs call against providers in order until one succeeds. Errors that
+// every provider would return, such as reverts, are not retried elsewhere.
+// With hedging enabled a slow attempt is raced against the next provider.
+func route[T any](ctx context.Context, p *providerPool, method string, call func(ctx context.Context, client chainClient) (T, error)) (T, error) {
+	var zero T
+
+	ordered := p.order()
+
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attempt[T], len(ordered))
+	next, inFlight := 0, 0
+
+	launch := func(hedged bool) {
+		provider := ordered[next]
+		next++
+		inFlight++
+		if hedged {
+			provider.countHedge()
+		}
+
+		go func() {
+			attemptCtx, cancel := p.attemptContext(callCtx)
+			defer cancel()
+
+			attemptCtx, span := tracer.Start(attemptCtx, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				attribute.String("rpc.system", "ethereum"),
+				attribute.String("rpc.method", method),
+				attribute.String("rpc.provider", provider.name),
+				attribute.Bool("rpc.hedged", hedged),
+			))
+
+			start := time.Now()
+			value, err := call(attemptCtx, provider.client)
+			p.observeCall(method, provider, time.Since(start), err)
+			endSpan(span, err)
+			if err == nil || callCtx.Err() == nil {
+				provider.observe(time.Since(start), failoverError(err))
+				if err == nil && p.hedge != nil {
+					p.hedge.observe(time.Since(start))
+				}
+			}
+			results <- attempt[T]{value: value, err: err, provider: provider}
+		}()
+	}
+
+	launch(false)
+
+	var hedgeTimer <-chan time.Time
+	if p.hedge != nil {
+		p.hedge.budget.earn()
+		if len(ordered) > 1 {
+			timer := time.NewTimer(p.hedge.delay())
+			defer timer.Stop()
+			hedgeTimer = timer.C
+		}
+	}
+
+	var lastErr error
+	for inFlight > 0 {
+		select {
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			if next < len(ordered) && p.hedge.budget.spend() {
+				launch(true)
+			}
+		case result := <-results:
+			inFlight--
+
+			if failoverError(result.err) == nil {
+				return result.value, result.err
+			}
+			if ctx.Err() != nil {
+				return zero, result.err
+			}
+			lastErr = fmt.Errorf("provider %s: %w", result.provider.name, result.err)
+
+			if inFlight == 0 && next < len(ordered) {
+				launch(false)
+			}
+		}
+	}
+
+	return zero, lastErr
+}
+
+// attemptContext bounds a single provider call so a hung provider leaves time
+// to fail over within the caller's deadline.
+func (p *providerPool) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.callTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.callTimeout)
+}
+
+// failoverError returns err unless it is a deterministic answer from the chain.
+func failoverError(err error) error {
+	if err == nil || classifyError(err).deterministic() {
+		return nil
+	}
+	return err
+}
+
+func (p *providerPool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return route(ctx, p, "eth_call", func(ctx context.Context, client chainClient) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (p *providerPool) BlockNumber(ctx context.Context) (uint64, error) {
+	return route(ctx, p, "eth_blockNumber", func(ctx context.Context, client chainClient) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+}
+
+func (p *providerPool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return route(ctx, p, "eth_getBlockByNumber", func(ctx context.Context, client chainClient) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (p *providerPool) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return route(ctx, p, "eth_getBlockByHash", func(ctx context.Context, client chainClient) (*types.Header, error) {
+		return client.HeaderByHash(ctx, hash)
+	})
+}
+
+func (p *providerPool) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return route(ctx, p, "eth_getLogs", func(ctx context.Context, client chainClient) ([]types.Log, error) {
+		return client.FilterLogs(ctx, q)
+	})
+}
+
+func (p *providerPool) ChainID(ctx context.Context) (*big.Int, error) {
+	return route(ctx, p, "eth_chainId", func(ctx context.Context, client chainClient) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
+}
+
+func (p *providerPool) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	return route(ctx, p, "eth_syncing", func(ctx context.Context, client chainClient) (*ethereum.SyncProgress, error) {
+		return client.SyncProgress(ctx)
+	})
+}
+
+// SubscribeNewHead subscribes on the best provider that supports it.
+func (p *providerPool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	var lastErr error = rpc.ErrNotificationsUnsupported
+
+	for _, provider := range p.order() {
+		sub, err := provider.client.SubscribeNewHead(ctx, ch)
+		if err == nil {
+			return sub, nil
+		}
+		if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			lastErr = fmt.Errorf("provider %s: %w", provider.name, err)
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (p *providerPool) status() []domain.ProviderStatus {
+	best := p.bestHead()
+
+	statuses := make([]domain.ProviderStatus, 0, len(p.providers))
+	for _, provider := range p.providers {
+		provider.mu.RLock()
+		status := domain.ProviderStatus{
+			Name:      provider.name,
+			Priority:  provider.priority,
+			Weight:    provider.weight,
+			LatencyMs: provider.latency.Milliseconds(),
+			ErrorRate: provider.errorRate,
+			Head:      provider.head,
+			HeadLag:   best - provider.head,
+			Calls:     provider.calls,
+			Failures:  provider.failures,
+			Hedges:    provider.hedges,
+			LastError: provider.lastError,
+		}
+		if !provider.lastErrorAt.IsZero() {
+			status.LastErrorAt = provider.lastErrorAt.UTC().Format(time.RFC3339)
+		}
+		provider.mu.RUnlock()
+
+		if provider.limiter != nil {
+			status.Throttled = provider.limiter.stats.throttled.Load()
+			status.Rejected = provider.limiter.stats.rejected.Load()
+		}
+
+		status.Healthy = status.ErrorRate < maxProviderErrRate && status.HeadLag <= maxProviderHeadLag
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// LimiterStats reports the rate limiter counters of every limited provider.
+func (e *EthereumService) LimiterStats() []domain.LimiterStats {
+	if e.providers == nil {
+		return nil
+	}
+
+	var stats []domain.LimiterStats
+	for _, provider := range e.providers.providers {
+		if provider.limiter == nil {
+			continue
+		}
+		stats = append(stats, domain.LimiterStats{
+			Provider:  provider.name,
+			Throttled: provider.limiter.stats.throttled.Load(),
+			Rejected:  provider.limiter.stats.rejected.Load(),
+		})
+	}
+
+	return stats
+}
+
+func (e *EthereumService) ProviderStatus(ctx context.Context) []domain.ProviderStatus {
+	if e.providers == nil {
+		return []domain.ProviderStatus{}
+	}
+	return e.providers.status()
+}
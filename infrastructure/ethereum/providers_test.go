@@ -0,0 +1,158 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type failingClient struct {
+	*fakeClient
+	err error
+}
+
+func (f *failingClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return 0, f.err
+}
+
+func (f *failingClient) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return nil, f.err
+}
+
+func TestProviderPoolFailover(t *testing.T) {
+	primary := &failingClient{fakeClient: newFakeClient(), err: errors.New("503 service unavailable")}
+	secondary := newFakeClient()
+
+	pool := &providerPool{providers: []*provider{
+		newProvider(ProviderConfig{Name: "primary", Priority: 0}, primary),
+		newProvider(ProviderConfig{Name: "secondary", Priority: 1}, secondary),
+	}}
+
+	head, err := pool.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if head != 100 {
+		t.Errorf("Expected head 100, got %d", head)
+	}
+
+	statuses := pool.status()
+	if statuses[0].Failures != 1 || statuses[0].LastError == "" {
+		t.Errorf("Expected failure recorded for primary, got %+v", statuses[0])
+	}
+	if statuses[1].Calls != 1 || statuses[1].Failures != 0 {
+		t.Errorf("Expected one successful call on secondary, got %+v", statuses[1])
+	}
+}
+
+type recordingObserver struct {
+	calls []string
+}
+
+func (r *recordingObserver) ObserveRPC(method, provider, outcome string, duration time.Duration) {
+	r.calls = append(r.calls, method+" "+provider+" "+outcome)
+}
+
+func TestProviderPoolReportsCallsToObserver(t *testing.T) {
+	primary := &failingClient{fakeClient: newFakeClient(), err: rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}}
+	observer := &recordingObserver{}
+
+	pool := &providerPool{
+		providers: []*provider{
+			newProvider(ProviderConfig{Name: "primary", Priority: 0}, primary),
+			newProvider(ProviderConfig{Name: "secondary", Priority: 1}, newFakeClient()),
+		},
+		observer: observer,
+	}
+
+	if _, err := pool.BlockNumber(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"eth_blockNumber primary unavailable", "eth_blockNumber secondary ok"}
+	if len(observer.calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, observer.calls)
+	}
+	for i := range expected {
+		if observer.calls[i] != expected[i] {
+			t.Errorf("Expected call %q, got %q", expected[i], observer.calls[i])
+		}
+	}
+}
+
+func TestProviderPoolDoesNotFailOverReverts(t *testing.T) {
+	primary := newFakeClient()
+	secondary := newFakeClient()
+
+	pool := &providerPool{providers: []*provider{
+		newProvider(ProviderConfig{Name: "primary", Priority: 0}, primary),
+		newProvider(ProviderConfig{Name: "secondary", Priority: 1}, secondary),
+	}}
+
+	service := newTestService(pool)
+	if _, err := service.GetPoolReserves(context.Background(), common.HexToAddress("0xdead").Hex()); err == nil {
+		t.Fatalf("Expected error for unknown pool")
+	}
+
+	if calls := secondary.callCount("token0"); calls != 0 {
+		t.Errorf("Expected revert not to be retried on secondary, got %d calls", calls)
+	}
+	if status := pool.status()[0]; status.Failures != 0 {
+		t.Errorf("Expected revert not to count against primary health, got %+v", status)
+	}
+}
+
+func TestProviderPoolOrder(t *testing.T) {
+	healthy := newProvider(ProviderConfig{Name: "healthy", Priority: 1}, newFakeClient())
+	lagging := newProvider(ProviderConfig{Name: "lagging", Priority: 0}, newFakeClient())
+	failing := newProvider(ProviderConfig{Name: "failing", Priority: 0}, newFakeClient())
+
+	healthy.observeHead(110)
+	lagging.observeHead(100)
+	failing.observeHead(110)
+	for i := 0; i < 10; i++ {
+		failing.observe(0, errors.New("timeout"))
+	}
+
+	pool := &providerPool{providers: []*provider{lagging, failing, healthy}}
+
+	ordered := pool.order()
+	if ordered[0] != healthy {
+		t.Errorf("Expected healthy provider first, got %s", ordered[0].name)
+	}
+
+	for _, status := range pool.status() {
+		if status.Healthy != (status.Name == "healthy") {
+			t.Errorf("Unexpected health for %s: %+v", status.Name, status)
+		}
+	}
+}
+
+func TestProviderPoolCallTimeoutFailsOver(t *testing.T) {
+	hung := &slowClient{fakeClient: newFakeClient(), delay: time.Minute}
+	healthy := newFakeClient()
+
+	pool := &providerPool{
+		providers: []*provider{
+			newProvider(ProviderConfig{Name: "hung", Priority: 0}, hung),
+			newProvider(ProviderConfig{Name: "healthy", Priority: 1}, healthy),
+		},
+		callTimeout: 20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := pool.BlockNumber(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status := pool.status()[0]; status.Failures != 1 {
+		t.Errorf("Expected the timed out call to count against the hung provider, got %+v", status)
+	}
+}
@@ -0,0 +1,226 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultQuorumProviders = 3
+
+	// quorumConfirmations keeps quorum reads a few blocks behind the head so a
+	// provider that is one or two blocks behind still has the block.
+	quorumConfirmations = 2
+)
+
+// QuorumConfig leaves Providers and Required at zero to ask up to
+// defaultQuorumProviders providers and require a majority of those asked.
+type QuorumConfig struct {
+	Providers int
+	Required  int
+}
+
+// resolveQuorum fills unset quorum settings for a pool of n providers.
+func resolveQuorum(config QuorumConfig, n int) QuorumConfig {
+	n = max(n, 1)
+	if config.Providers <= 0 {
+		config.Providers = min(n, defaultQuorumProviders)
+	}
+	if config.Required <= 0 {
+		config.Required = min(config.Providers, n)/2 + 1
+	}
+	return config
+}
+
+// hashCaller is implemented by clients that can pin eth_call to a block hash,
+// so every provider in a quorum reads the same block even across reorgs.
+type hashCaller interface {
+	CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error)
+}
+
+type quorumVote struct {
+	provider string
+	reserve0 *big.Int
+	reserve1 *big.Int
+	err      error
+}
+
+// GetPoolReservesQuorum reads getReserves from several providers at the same
+// block hash, quorumConfirmations below the head, and only returns reserves
+// that enough of them agree on.
+func (e *EthereumService) GetPoolReservesQuorum(ctx context.Context, poolAddress string) (result *domain.PoolReserves, err error) {
+	ctx, span := tracer.Start(ctx, "EthereumService.GetPoolReservesQuorum", trace.WithAttributes(attribute.String("pool.address", poolAddress)))
+	defer func() {
+		if result != nil {
+			span.SetAttributes(attribute.Int64("block.number", int64(result.BlockNumber)))
+		}
+		endSpan(span, err)
+	}()
+
+	if !common.IsHexAddress(poolAddress) {
+		return nil, fmt.Errorf("%w: invalid pool address: %s", domain.ErrInvalidRequest, poolAddress)
+	}
+	if e.providers == nil {
+		return nil, fmt.Errorf("quorum reads require a provider pool")
+	}
+
+	poolContract := common.HexToAddress(poolAddress)
+
+	token0Address, token1Address, err := e.getPoolTokens(ctx, poolContract)
+	if err != nil {
+		return nil, err
+	}
+
+	head, tracked := e.trackedHead()
+	if !tracked {
+		head, err = e.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block number: %w", err)
+		}
+	}
+
+	number := head - min(head, quorumConfirmations)
+	header, err := e.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", number, err)
+	}
+	blockHash := header.Hash()
+
+	data, err := e.uniswapV2ABI.Pack("getReserves")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack method getReserves: %w", err)
+	}
+
+	ordered := e.providers.order()
+	if len(ordered) > e.quorum.Providers {
+		ordered = ordered[:e.quorum.Providers]
+	}
+
+	votes := make([]quorumVote, len(ordered))
+	var wg sync.WaitGroup
+	for i, provider := range ordered {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			votes[i] = e.quorumVote(ctx, provider, poolContract, data, blockHash)
+		}()
+	}
+	wg.Wait()
+
+	report := tallyQuorum(votes, e.quorum.Required)
+	report.BlockNumber = header.Number.Uint64()
+	report.BlockHash = blockHash.Hex()
+
+	if report.Agreed < report.Required {
+		return nil, &domain.QuorumError{Report: report}
+	}
+
+	var reserve0, reserve1 *big.Int
+	for _, vote := range votes {
+		if vote.err == nil && vote.reserve0.String() == report.Reserve0 && vote.reserve1.String() == report.Reserve1 {
+			reserve0, reserve1 = vote.reserve0, vote.reserve1
+			break
+		}
+	}
+
+	return &domain.PoolReserves{
+		Reserve0:    reserve0,
+		Reserve1:    reserve1,
+		Token0:      token0Address.Hex(),
+		Token1:      token1Address.Hex(),
+		BlockNumber: report.BlockNumber,
+		Quorum:      report,
+	}, nil
+}
+
+func (e *EthereumService) quorumVote(ctx context.Context, provider *provider, pool common.Address, data []byte, blockHash common.Hash) quorumVote {
+	vote := quorumVote{provider: provider.name}
+
+	caller, ok := provider.client.(hashCaller)
+	if !ok {
+		vote.err = fmt.Errorf("provider cannot call at block hash")
+		return vote
+	}
+
+	ctx, cancel := e.providers.attemptContext(ctx)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "eth_call", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("rpc.system", "ethereum"),
+		attribute.String("rpc.method", "eth_call"),
+		attribute.String("rpc.provider", provider.name),
+		attribute.String("block.hash", blockHash.Hex()),
+	))
+	start := time.Now()
+	result, err := caller.CallContractAtHash(ctx, ethereum.CallMsg{To: &pool, Data: data}, blockHash)
+	e.providers.observeCall("eth_call", provider, time.Since(start), err)
+	endSpan(span, err)
+	if err != nil {
+		vote.err = err
+		return vote
+	}
+
+	var reserves struct {
+		Reserve0           *big.Int
+		Reserve1           *big.Int
+		BlockTimestampLast uint32
+	}
+	if err := e.uniswapV2ABI.UnpackIntoInterface(&reserves, "getReserves", result); err != nil {
+		vote.err = fmt.Errorf("failed to unpack reserves data: %w", err)
+		return vote
+	}
+
+	vote.reserve0, vote.reserve1 = reserves.Reserve0, reserves.Reserve1
+
+	return vote
+}
+
+// tallyQuorum picks the reserves most providers returned and marks the votes
+// that agree with them.
+func tallyQuorum(votes []quorumVote, required int) *domain.QuorumReport {
+	counts := make(map[string]int)
+	best := ""
+	for _, vote := range votes {
+		if vote.err != nil {
+			continue
+		}
+		key := vote.reserve0.String() + "/" + vote.reserve1.String()
+		counts[key]++
+		if counts[key] > counts[best] {
+			best = key
+		}
+	}
+
+	report := &domain.QuorumReport{
+		Required: required,
+		Queried:  len(votes),
+		Votes:    make([]domain.QuorumVote, 0, len(votes)),
+	}
+
+	for _, vote := range votes {
+		entry := domain.QuorumVote{Provider: vote.provider}
+		if vote.err != nil {
+			entry.Error = vote.err.Error()
+		} else {
+			entry.Reserve0 = vote.reserve0.String()
+			entry.Reserve1 = vote.reserve1.String()
+			entry.Agrees = entry.Reserve0+"/"+entry.Reserve1 == best
+			if entry.Agrees {
+				report.Agreed++
+				report.Reserve0, report.Reserve1 = entry.Reserve0, entry.Reserve1
+			}
+		}
+		report.Votes = append(report.Votes, entry)
+	}
+
+	return report
+}
@@ -0,0 +1,109 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetPoolReservesQuorum(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+
+	newClient := func(reserve0 int64) *fakeClient {
+		client := newFakeClient()
+		client.pools[common.HexToAddress(poolAddress)] = fakePool{
+			token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			reserve0: big.NewInt(reserve0),
+			reserve1: big.NewInt(2000),
+		}
+		return client
+	}
+
+	tests := []struct {
+		name           string
+		clients        []chainClient
+		expectedAgreed int
+		expectError    bool
+	}{
+		{
+			name:           "All providers agree",
+			clients:        []chainClient{newClient(1000), newClient(1000), newClient(1000)},
+			expectedAgreed: 3,
+		},
+		{
+			name:           "Majority agrees",
+			clients:        []chainClient{newClient(1000), newClient(999), newClient(1000)},
+			expectedAgreed: 2,
+		},
+		{
+			name:        "Providers disagree",
+			clients:     []chainClient{newClient(1000), newClient(999), &failingClient{fakeClient: newClient(1000), err: errors.New("timeout")}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &providerPool{}
+			for i, client := range tt.clients {
+				pool.providers = append(pool.providers, newProvider(ProviderConfig{Name: string(rune('a' + i))}, client))
+			}
+
+			service := newTestService(pool)
+			service.providers = pool
+			service.quorum = QuorumConfig{Providers: 3, Required: 2}
+
+			reserves, err := service.GetPoolReservesQuorum(context.Background(), poolAddress)
+
+			if tt.expectError {
+				var quorumErr *domain.QuorumError
+				if !errors.As(err, &quorumErr) || !errors.Is(err, domain.ErrQuorumNotReached) {
+					t.Fatalf("Expected quorum error, got %v", err)
+				}
+				if len(quorumErr.Report.Votes) != 3 {
+					t.Errorf("Expected every vote in the report, got %+v", quorumErr.Report)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if reserves.Reserve0.Int64() != 1000 || reserves.Quorum == nil || reserves.Quorum.Agreed != tt.expectedAgreed {
+				t.Errorf("Unexpected quorum result: reserve0=%s report=%+v", reserves.Reserve0, reserves.Quorum)
+			}
+			if reserves.BlockNumber != 100-quorumConfirmations {
+				t.Errorf("Expected the quorum block %d below the head, got %d", quorumConfirmations, reserves.BlockNumber)
+			}
+		})
+	}
+}
+
+func TestResolveQuorum(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    QuorumConfig
+		providers int
+		expected  QuorumConfig
+	}{
+		{name: "Single provider", providers: 1, expected: QuorumConfig{Providers: 1, Required: 1}},
+		{name: "Two providers", providers: 2, expected: QuorumConfig{Providers: 2, Required: 2}},
+		{name: "Many providers", providers: 5, expected: QuorumConfig{Providers: 3, Required: 2}},
+		{name: "Explicit settings are kept", config: QuorumConfig{Providers: 4, Required: 4}, providers: 5, expected: QuorumConfig{Providers: 4, Required: 4}},
+		{name: "Majority of the providers asked", config: QuorumConfig{Providers: 5}, providers: 3, expected: QuorumConfig{Providers: 5, Required: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveQuorum(tt.config, tt.providers); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type blockRef struct {
+	hash       common.Hash
+	parentHash common.Hash
+}
+
+// blockChain remembers the hashes of the last maxReorgDepth canonical blocks
+// so a new head whose parent hash does not match can be traced back to the
+// common ancestor.
+type blockChain struct {
+	mu     sync.RWMutex
+	blocks map[uint64]blockRef
+	tip    uint64
+
+	reorgCount     uint64
+	lastReorgDepth uint64
+	lastReorgBlock uint64
+	lastReorgAt    time.Time
+}
+
+type reorg struct {
+	ancestor uint64
+	depth    uint64
+}
+
+func newBlockChain() *blockChain {
+	return &blockChain{
+		blocks: make(map[uint64]blockRef),
+	}
+}
+
+func (c *blockChain) hashAt(number uint64) (common.Hash, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ref, ok := c.blocks[number]
+	return ref.hash, ok
+}
+
+func (c *blockChain) empty() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.blocks) == 0
+}
+
+// link records path (ordered by ascending number) as canonical above ancestor
+// and reports a reorg if previously known blocks were orphaned. That includes
+// a head below the tip: the blocks above it are no longer canonical.
+func (c *blockChain) link(ancestor uint64, path []*types.Header) *reorg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	common := ancestor
+	for _, header := range path {
+		ref, ok := c.blocks[header.Number.Uint64()]
+		if !ok || ref.hash != header.Hash() {
+			break
+		}
+		common = header.Number.Uint64()
+	}
+
+	var detected *reorg
+	if c.tip > common && len(c.blocks) > 0 {
+		detected = &reorg{ancestor: common, depth: c.tip - common}
+		c.record(detected)
+	}
+
+	for number := range c.blocks {
+		if number > ancestor {
+			delete(c.blocks, number)
+		}
+	}
+
+	for _, header := range path {
+		c.blocks[header.Number.Uint64()] = blockRef{hash: header.Hash(), parentHash: header.ParentHash}
+	}
+	c.tip = path[len(path)-1].Number.Uint64()
+
+	if c.tip > maxReorgDepth {
+		for number := range c.blocks {
+			if number <= c.tip-maxReorgDepth {
+				delete(c.blocks, number)
+			}
+		}
+	}
+
+	return detected
+}
+
+// record updates the reorg counters; c.mu must be held.
+func (c *blockChain) record(r *reorg) {
+	c.reorgCount++
+	c.lastReorgDepth = r.depth
+	c.lastReorgBlock = r.ancestor
+	c.lastReorgAt = time.Now()
+}
+
+func (c *blockChain) reset(header *types.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.blocks)
+	c.blocks[header.Number.Uint64()] = blockRef{hash: header.Hash(), parentHash: header.ParentHash}
+	c.tip = header.Number.Uint64()
+}
+
+// resetAfterReorg restarts tracking at header after a reorg deeper than the
+// tracked window.
+func (c *blockChain) resetAfterReorg(header *types.Header, r *reorg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.blocks)
+	c.blocks[header.Number.Uint64()] = blockRef{hash: header.Hash(), parentHash: header.ParentHash}
+	c.tip = header.Number.Uint64()
+	c.record(r)
+}
+
+func (c *blockChain) tipHash() common.Hash {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.blocks[c.tip].hash
+}
+
+func (c *blockChain) tipNumber() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.tip
+}
+
+func (c *blockChain) status() domain.ReorgStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status := domain.ReorgStatus{
+		Count:     c.reorgCount,
+		LastDepth: c.lastReorgDepth,
+		LastBlock: c.lastReorgBlock,
+	}
+	if !c.lastReorgAt.IsZero() {
+		status.LastAt = c.lastReorgAt.UTC().Format(time.RFC3339)
+	}
+
+	return status
+}
+
+// observeHead walks back from header through parent hashes until it reaches a
+// block already known to be canonical. Missing ancestors are fetched by hash.
+func (e *EthereumService) observeHead(ctx context.Context, header *types.Header) (*reorg, error) {
+	if e.chain.empty() {
+		e.chain.reset(header)
+		return nil, nil
+	}
+
+	// A head far past the tip means the follower fell behind, not that the
+	// chain reorganized; walking back would only exhaust the window.
+	if tip := e.chain.tipNumber(); header.Number.Uint64() > tip+maxReorgDepth {
+		e.logger.WarnContext(ctx, "head jumped past the tracked window, resetting", "tip", tip, "block", header.Number.Uint64())
+		e.chain.reset(header)
+		return nil, nil
+	}
+
+	path := []*types.Header{header}
+	current := header
+
+	for depth := 0; depth <= maxReorgDepth; depth++ {
+		number := current.Number.Uint64()
+		if number == 0 {
+			break
+		}
+
+		if known, ok := e.chain.hashAt(number - 1); ok && known == current.ParentHash {
+			return e.chain.link(number-1, path), nil
+		}
+
+		parent, err := e.client.HeaderByHash(ctx, current.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch parent header %s: %w", current.ParentHash.Hex(), err)
+		}
+
+		path = append([]*types.Header{parent}, path...)
+		current = parent
+	}
+
+	// No common ancestor within the tracked window: everything derived from
+	// the old chain is suspect.
+	ancestor := uint64(0)
+	if header.Number.Uint64() > maxReorgDepth {
+		ancestor = header.Number.Uint64() - maxReorgDepth
+	}
+	detected := &reorg{ancestor: ancestor, depth: maxReorgDepth}
+	e.chain.resetAfterReorg(header, detected)
+
+	return detected, nil
+}
+
+// handleReorg drops everything derived from blocks above the common ancestor.
+func (e *EthereumService) handleReorg(r *reorg) {
+	e.logger.Warn("chain reorganization detected", "depth", r.depth, "ancestor", r.ancestor)
+
+	e.poolState.rollback(r.ancestor)
+	// No fetch carries the zero hash, so nothing is cached until the new head.
+	e.reserveCache.invalidate(r.ancestor, common.Hash{})
+	e.invalidateTokenDataAbove(r.ancestor)
+
+	if e.poolWriter != nil {
+		e.poolWriter.deleteAbove(r.ancestor)
+	}
+}
+
+func (e *EthereumService) invalidateTokenDataAbove(block uint64) {
+	e.tokenAddresses.removeIf(func(_ common.Address, tokens poolTokens) bool {
+		return tokens.block > block
+	})
+
+	e.tokenInfoCache.removeIf(func(_ common.Address, entry tokenInfoEntry) bool {
+		return entry.block > block
+	})
+}
+
+func (e *EthereumService) ChainStatus(ctx context.Context) domain.ChainStatus {
+	e.head.mu.RLock()
+	status := domain.ChainStatus{
+		HeadBlock: e.head.number,
+		HeadHash:  e.head.hash.Hex(),
+	}
+	if !e.head.updatedAt.IsZero() {
+		status.HeadUpdatedAt = e.head.updatedAt.UTC().Format(time.RFC3339)
+	}
+	e.head.mu.RUnlock()
+
+	status.Reorgs = e.chain.status()
+	status.Breaker = e.BreakerStatus()
+	status.Caches = e.CacheStats()
+
+	return status
+}
@@ -0,0 +1,193 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestHandleHeadDetectsReorg(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+	pool := common.HexToAddress(poolAddress)
+
+	client := newFakeClient()
+	client.pools[pool] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	service.syncLogs = true
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	canonical := client.extendChain(genesis, 5, 0)
+
+	service.handleHead(ctx, genesis)
+	if _, err := service.GetPoolReserves(ctx, poolAddress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.addSyncLog(pool, 104, 0, 900, 2200)
+	for _, header := range canonical {
+		service.handleHead(ctx, header)
+	}
+
+	reserves, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 900 || reserves.BlockNumber != 105 {
+		t.Fatalf("Expected reserves from block 104 at head 105, got %s at %d", reserves.Reserve0, reserves.BlockNumber)
+	}
+
+	service.tokenAddresses.set(common.HexToAddress("0x3333333333333333333333333333333333333333"), poolTokens{block: 105})
+
+	// Blocks 104 and 105 are replaced by a fork that reaches 106.
+	client.mu.Lock()
+	client.logs = nil
+	client.mu.Unlock()
+	client.addSyncLog(pool, 105, 0, 1300, 1600)
+
+	fork := client.extendChain(canonical[2], 3, 1)
+	service.handleHead(ctx, fork[2])
+
+	status := service.ChainStatus(ctx)
+	if status.Reorgs.Count != 1 || status.Reorgs.LastDepth != 2 || status.Reorgs.LastBlock != 103 {
+		t.Errorf("Unexpected reorg status: %+v", status.Reorgs)
+	}
+	if status.HeadBlock != 106 || status.HeadHash != fork[2].Hash().Hex() {
+		t.Errorf("Expected head at fork block 106, got %d %s", status.HeadBlock, status.HeadHash)
+	}
+
+	reserves, err = service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 1300 || reserves.BlockNumber != 106 {
+		t.Errorf("Expected reserves replayed on the fork, got %s at %d", reserves.Reserve0, reserves.BlockNumber)
+	}
+
+	if _, _, exists := service.tokenAddresses.get(common.HexToAddress("0x3333333333333333333333333333333333333333")); exists {
+		t.Errorf("Expected token data read at an orphaned block to be dropped")
+	}
+
+	if calls := client.callCount("getReserves"); calls != 1 {
+		t.Errorf("Expected reserves to come from Sync logs after seeding, got %d getReserves calls", calls)
+	}
+}
+
+func TestHandleHeadKnownBlocks(t *testing.T) {
+	pool := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	client := newFakeClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	headers := client.extendChain(genesis, 3, 0)
+
+	service.handleHead(ctx, genesis)
+	for _, header := range headers {
+		service.handleHead(ctx, header)
+	}
+
+	// Repeating the tip only refreshes it.
+	service.handleHead(ctx, headers[2])
+	if status := service.ChainStatus(ctx); status.HeadBlock != 103 || status.Reorgs.Count != 0 {
+		t.Fatalf("Expected the repeated tip to be ignored, got head %d with %d reorgs", status.HeadBlock, status.Reorgs.Count)
+	}
+
+	service.reserveCache.put(pool, headers[2].Hash(), &domain.PoolReserves{Reserve0: big.NewInt(1), BlockNumber: 103})
+
+	// The chain returns to a block it already passed: block 103 is orphaned.
+	service.handleHead(ctx, headers[1])
+
+	status := service.ChainStatus(ctx)
+	if status.HeadBlock != 102 || status.HeadHash != headers[1].Hash().Hex() {
+		t.Errorf("Expected head back at block 102, got %d %s", status.HeadBlock, status.HeadHash)
+	}
+	if status.Reorgs.Count != 1 || status.Reorgs.LastDepth != 1 || status.Reorgs.LastBlock != 102 {
+		t.Errorf("Expected the orphaned block to be recorded as a reorg, got %+v", status.Reorgs)
+	}
+	if _, ok := service.reserveCache.get(pool, 103); ok {
+		t.Errorf("Expected reserves from the orphaned block to be dropped")
+	}
+
+	// Block 103 comes back on top of 102.
+	service.handleHead(ctx, headers[2])
+	if status := service.ChainStatus(ctx); status.HeadBlock != 103 || status.Reorgs.Count != 1 {
+		t.Errorf("Expected block 103 to extend the chain, got head %d with %d reorgs", status.HeadBlock, status.Reorgs.Count)
+	}
+}
+
+func TestHandleHeadFetchesMissedBlocks(t *testing.T) {
+	client := newFakeClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	headers := client.extendChain(genesis, 4, 0)
+
+	service.handleHead(ctx, genesis)
+	service.handleHead(ctx, headers[3])
+
+	status := service.ChainStatus(ctx)
+	if status.HeadBlock != 104 || status.Reorgs.Count != 0 {
+		t.Errorf("Expected gap to be filled without a reorg, got head %d with %d reorgs", status.HeadBlock, status.Reorgs.Count)
+	}
+	if calls := client.callCount("headerByHash"); calls != 3 {
+		t.Errorf("Expected 3 missed headers to be fetched, got %d", calls)
+	}
+}
+
+func TestHandleHeadResetsOnLargeGap(t *testing.T) {
+	client := newFakeClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	headers := client.extendChain(genesis, maxReorgDepth+10, 0)
+
+	service.handleHead(ctx, genesis)
+	service.handleHead(ctx, headers[len(headers)-1])
+
+	status := service.ChainStatus(ctx)
+	if status.HeadBlock != 174 || status.Reorgs.Count != 0 {
+		t.Errorf("Expected a gap to reset without a reorg, got head %d with %d reorgs", status.HeadBlock, status.Reorgs.Count)
+	}
+	if calls := client.callCount("headerByHash"); calls != 0 {
+		t.Errorf("Expected no walk back over a gap, got %d header fetches", calls)
+	}
+}
+
+func TestHandleHeadRecordsDeepReorg(t *testing.T) {
+	client := newFakeClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	genesis := genesisHeader(100)
+	canonical := client.extendChain(genesis, maxReorgDepth+6, 0)
+
+	service.handleHead(ctx, genesis)
+	for _, header := range canonical {
+		service.handleHead(ctx, header)
+	}
+
+	// The fork leaves the canonical chain below the tracked window.
+	fork := client.extendChain(genesis, maxReorgDepth+10, 1)
+	service.handleHead(ctx, fork[len(fork)-1])
+
+	status := service.ChainStatus(ctx)
+	if status.HeadBlock != 174 || status.HeadHash != fork[len(fork)-1].Hash().Hex() {
+		t.Errorf("Expected head at fork block 174, got %d %s", status.HeadBlock, status.HeadHash)
+	}
+	if status.Reorgs.Count != 1 || status.Reorgs.LastDepth != maxReorgDepth || status.Reorgs.LastBlock != 110 || status.Reorgs.LastAt == "" {
+		t.Errorf("Expected the deep reorg to be recorded, got %+v", status.Reorgs)
+	}
+}
@@ -0,0 +1,65 @@
+package ethereum
+
+import (
+	"sync"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// reserveCache holds pool reserves for a single block and is emptied on every
+// new head. The block hash is kept so a fetch against a block orphaned at the
+// same height cannot fill the cache for its replacement.
+type reserveCache struct {
+	mu      sync.RWMutex
+	block   uint64
+	hash    common.Hash
+	entries map[common.Address]*domain.PoolReserves
+}
+
+func newReserveCache() *reserveCache {
+	return &reserveCache{
+		entries: make(map[common.Address]*domain.PoolReserves),
+	}
+}
+
+func (c *reserveCache) get(pool common.Address, block uint64) (*domain.PoolReserves, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.block != block {
+		return nil, false
+	}
+
+	reserves, ok := c.entries[pool]
+	if !ok {
+		return nil, false
+	}
+
+	cached := *reserves
+	cached.Cached = true
+
+	return &cached, true
+}
+
+// put stores reserves read at the block with the given hash, unless the
+// cache has moved on to another block in the meantime.
+func (c *reserveCache) put(pool common.Address, hash common.Hash, reserves *domain.PoolReserves) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if reserves.BlockNumber != c.block || hash != c.hash {
+		return
+	}
+
+	c.entries[pool] = reserves
+}
+
+func (c *reserveCache) invalidate(block uint64, hash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.block = block
+	c.hash = hash
+	clear(c.entries)
+}
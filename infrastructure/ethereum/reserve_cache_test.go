@@ -0,0 +1,149 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetPoolReservesCachedWithinBlock(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+
+	client := newFakeClient()
+	client.pools[common.HexToAddress(poolAddress)] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	ctx := context.Background()
+
+	head := genesisHeader(100)
+	service.handleHead(ctx, head)
+
+	first, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.Cached || first.BlockNumber != 100 {
+		t.Errorf("Expected fresh reserves at block 100, got cached=%v block=%d", first.Cached, first.BlockNumber)
+	}
+
+	second, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !second.Cached || second.BlockNumber != 100 {
+		t.Errorf("Expected cached reserves at block 100, got cached=%v block=%d", second.Cached, second.BlockNumber)
+	}
+
+	if calls := client.callCount("getReserves"); calls != 1 {
+		t.Errorf("Expected 1 getReserves call, got %d", calls)
+	}
+	if calls := client.callCount("blockNumber"); calls != 0 {
+		t.Errorf("Expected no blockNumber calls while tracking heads, got %d", calls)
+	}
+
+	service.handleHead(ctx, client.extendChain(head, 1, 0)[0])
+
+	third, err := service.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if third.Cached || third.BlockNumber != 101 {
+		t.Errorf("Expected fresh reserves at block 101, got cached=%v block=%d", third.Cached, third.BlockNumber)
+	}
+
+	if calls := client.callCount("getReserves"); calls != 2 {
+		t.Errorf("Expected 2 getReserves calls, got %d", calls)
+	}
+	if calls := client.callCount("token0"); calls != 1 {
+		t.Errorf("Expected pool tokens to be fetched once, got %d", calls)
+	}
+}
+
+func TestGetPoolReservesWithoutHeadTracking(t *testing.T) {
+	poolAddress := "0x1234567890123456789012345678901234567890"
+
+	client := newFakeClient()
+	client.pools[common.HexToAddress(poolAddress)] = fakePool{
+		token0:   common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token1:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		reserve0: big.NewInt(1000),
+		reserve1: big.NewInt(2000),
+	}
+
+	service := newTestService(client)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		reserves, err := service.GetPoolReserves(ctx, poolAddress)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if reserves.Cached {
+			t.Errorf("Expected uncached reserves without head tracking")
+		}
+	}
+
+	if calls := client.callCount("getReserves"); calls != 2 {
+		t.Errorf("Expected 2 getReserves calls, got %d", calls)
+	}
+}
+
+func TestPollHeadsTracksLatestHead(t *testing.T) {
+	client := newFakeClient()
+	service := newTestService(client)
+	service.headPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service.Start(ctx)
+
+	waitForHead(t, service, 100)
+	client.setHead(105)
+	waitForHead(t, service, 105)
+}
+
+func waitForHead(t *testing.T, service *EthereumService, expected uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if head, ok := service.trackedHead(); ok && head == expected {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("Head %d was not observed", expected)
+}
+
+func TestReserveCacheRejectsOrphanedFetch(t *testing.T) {
+	pool := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	orphaned := common.HexToHash("0x01")
+	replacement := common.HexToHash("0x02")
+
+	cache := newReserveCache()
+	cache.invalidate(100, orphaned)
+
+	// Block 100 is replaced at the same height while a fetch against it is in flight.
+	cache.invalidate(100, replacement)
+	cache.put(pool, orphaned, &domain.PoolReserves{Reserve0: big.NewInt(1), BlockNumber: 100})
+
+	if cached, ok := cache.get(pool, 100); ok {
+		t.Fatalf("Expected reserves from the orphaned block to be rejected, got %+v", cached)
+	}
+
+	cache.put(pool, replacement, &domain.PoolReserves{Reserve0: big.NewInt(2), BlockNumber: 100})
+	if cached, ok := cache.get(pool, 100); !ok || cached.Reserve0.Int64() != 2 || !cached.Cached {
+		t.Errorf("Expected reserves from the canonical block to be cached, got %+v", cached)
+	}
+}
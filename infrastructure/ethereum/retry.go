@@ -0,0 +1,155 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 2 * time.Second
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func defaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+	}
+}
+
+// backoff returns an exponential delay for the given retry with the upper
+// half randomised, so concurrent callers spread out.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << retry
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay < 2 {
+		return delay
+	}
+	return delay/2 + rand.N(delay/2)
+}
+
+// retryClient retries transient failures of the wrapped client. A retry is
+// only scheduled if it can start before the caller's deadline.
+type retryClient struct {
+	next   chainClient
+	policy RetryPolicy
+}
+
+func newRetryClient(next chainClient, policy RetryPolicy) *retryClient {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &retryClient{next: next, policy: policy}
+}
+
+func (r *retryClient) do(ctx context.Context, call func() error) error {
+	var err error
+
+	for attempt := 0; attempt < r.policy.MaxAttempts; attempt++ {
+		if err = call(); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || !classifyError(err).transient() || attempt == r.policy.MaxAttempts-1 {
+			break
+		}
+
+		delay := r.policy.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return classified(err)
+		case <-timer.C:
+		}
+	}
+
+	return classified(err)
+}
+
+func (r *retryClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var result uint64
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.BlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var result *types.Header
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var result *types.Header
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.HeaderByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.FilterLogs(ctx, q)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.ChainID(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	var result *ethereum.SyncProgress
+	err := r.do(ctx, func() (err error) {
+		result, err = r.next.SyncProgress(ctx)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeNewHead is not retried; the head tracker resubscribes on its own.
+func (r *retryClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return r.next.SubscribeNewHead(ctx, ch)
+}
@@ -0,0 +1,91 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type scriptedClient struct {
+	*fakeClient
+	mu   sync.Mutex
+	errs []error
+}
+
+func (s *scriptedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fakeClient.calls["blockNumber"]++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return 0, err
+	}
+	return s.fakeClient.head, nil
+}
+
+func TestRetryClient(t *testing.T) {
+	rateLimited := rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}
+	reverted := errors.New("execution reverted")
+
+	tests := []struct {
+		name          string
+		errs          []error
+		timeout       time.Duration
+		expectedCalls int
+		expectError   bool
+	}{
+		{
+			name:          "Transient errors are retried",
+			errs:          []error{rateLimited, rateLimited},
+			expectedCalls: 3,
+		},
+		{
+			name:          "Attempts are bounded",
+			errs:          []error{rateLimited, rateLimited, rateLimited, rateLimited},
+			expectedCalls: 3,
+			expectError:   true,
+		},
+		{
+			name:          "Reverts are not retried",
+			errs:          []error{reverted},
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			name:          "No retry past the deadline",
+			errs:          []error{rateLimited, rateLimited},
+			timeout:       time.Millisecond,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &scriptedClient{fakeClient: newFakeClient(), errs: tt.errs}
+			retry := newRetryClient(client, RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond})
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			_, err := retry.BlockNumber(ctx)
+			if tt.expectError != (err != nil) {
+				t.Errorf("Expected error %v, got %v", tt.expectError, err)
+			}
+
+			if calls := client.callCount("blockNumber"); calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	tokensBucket = []byte("tokens")
+	poolsBucket  = []byte("pools")
+)
+
+// BoltStore persists token metadata and pool state in a single embedded
+// bbolt file so caches survive restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{tokensBucket, poolsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize state store: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) LoadTokens() ([]domain.TokenInfo, error) {
+	var tokens []domain.TokenInfo
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokensBucket).ForEach(func(_, value []byte) error {
+			var token domain.TokenInfo
+			if err := json.Unmarshal(value, &token); err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *BoltStore) SaveToken(token domain.TokenInfo) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	err = s.db.Batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokensBucket).Put(key(token.Address), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BoltStore) LoadPools() ([]domain.StoredPool, error) {
+	var pools []domain.StoredPool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(poolsBucket).ForEach(func(_, value []byte) error {
+			var pool domain.StoredPool
+			if err := json.Unmarshal(value, &pool); err != nil {
+				return err
+			}
+			pools = append(pools, pool)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pools: %w", err)
+	}
+
+	return pools, nil
+}
+
+// SavePools writes all pools in one transaction. A pool without reserves only
+// updates its token pair and keeps previously stored reserves.
+func (s *BoltStore) SavePools(pools []domain.StoredPool) error {
+	err := s.db.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(poolsBucket)
+
+		for _, pool := range pools {
+			if pool.Reserve0 == nil || pool.Reserve1 == nil {
+				if existing := bucket.Get(key(pool.Address)); existing != nil {
+					var stored domain.StoredPool
+					if err := json.Unmarshal(existing, &stored); err != nil {
+						return err
+					}
+					pool.Reserve0 = stored.Reserve0
+					pool.Reserve1 = stored.Reserve1
+					pool.BlockNumber = stored.BlockNumber
+				}
+			}
+
+			value, err := json.Marshal(pool)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(key(pool.Address), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save pools: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteReservesAbove forgets reserves recorded at orphaned blocks while
+// keeping the pool's token pair.
+func (s *BoltStore) DeleteReservesAbove(block uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(poolsBucket)
+		orphaned := make(map[string]domain.StoredPool)
+
+		err := bucket.ForEach(func(k, value []byte) error {
+			var pool domain.StoredPool
+			if err := json.Unmarshal(value, &pool); err != nil {
+				return err
+			}
+			if pool.BlockNumber > block {
+				orphaned[string(k)] = pool
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for k, pool := range orphaned {
+			pool.Reserve0, pool.Reserve1, pool.BlockNumber = nil, nil, 0
+			value, err := json.Marshal(pool)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(k), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete orphaned reserves: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func key(address string) []byte {
+	return []byte(strings.ToLower(address))
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestBoltStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "state.db")
+
+	s, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	if err := s.SaveToken(domain.TokenInfo{Address: "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2", Symbol: "MKR", Decimals: 18}); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	pool := domain.StoredPool{
+		Address:     "0x1234567890123456789012345678901234567890",
+		Token0:      "0x1111111111111111111111111111111111111111",
+		Token1:      "0x2222222222222222222222222222222222222222",
+		Reserve0:    bigIntFromString("10000000000000000000"),
+		Reserve1:    big.NewInt(2000),
+		BlockNumber: 100,
+	}
+	if err := s.SavePools([]domain.StoredPool{pool}); err != nil {
+		t.Fatalf("Failed to save pool: %v", err)
+	}
+
+	tokensOnly := domain.StoredPool{Address: pool.Address, Token0: pool.Token0, Token1: pool.Token1}
+	if err := s.SavePools([]domain.StoredPool{tokensOnly}); err != nil {
+		t.Fatalf("Failed to save pool tokens: %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Failed to close store: %v", err)
+	}
+
+	s, err = NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer s.Close()
+
+	tokens, err := s.LoadTokens()
+	if err != nil {
+		t.Fatalf("Failed to load tokens: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Symbol != "MKR" || tokens[0].Decimals != 18 {
+		t.Errorf("Unexpected tokens: %+v", tokens)
+	}
+
+	pools, err := s.LoadPools()
+	if err != nil {
+		t.Fatalf("Failed to load pools: %v", err)
+	}
+	if len(pools) != 1 {
+		t.Fatalf("Expected 1 pool, got %d", len(pools))
+	}
+	if pools[0].Reserve0.Cmp(pool.Reserve0) != 0 || pools[0].BlockNumber != 100 {
+		t.Errorf("Expected reserves to survive a tokens-only update, got %+v", pools[0])
+	}
+}
+
+func TestBoltStoreDeleteReservesAbove(t *testing.T) {
+	s, err := NewBoltStore(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	err = s.SavePools([]domain.StoredPool{
+		{Address: "0x1000000000000000000000000000000000000001", Reserve0: big.NewInt(1), Reserve1: big.NewInt(1), BlockNumber: 100},
+		{Address: "0x1000000000000000000000000000000000000002", Reserve0: big.NewInt(2), Reserve1: big.NewInt(2), BlockNumber: 105},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save pools: %v", err)
+	}
+
+	if err := s.DeleteReservesAbove(102); err != nil {
+		t.Fatalf("Failed to delete reserves: %v", err)
+	}
+
+	pools, err := s.LoadPools()
+	if err != nil {
+		t.Fatalf("Failed to load pools: %v", err)
+	}
+
+	for _, pool := range pools {
+		switch pool.BlockNumber {
+		case 100:
+			if pool.Reserve0 == nil {
+				t.Errorf("Expected reserves below the ancestor to be kept")
+			}
+		case 0:
+			if pool.Reserve0 != nil {
+				t.Errorf("Expected orphaned reserves to be cleared")
+			}
+		default:
+			t.Errorf("Unexpected pool block %d", pool.BlockNumber)
+		}
+	}
+}
+
+func bigIntFromString(s string) *big.Int {
+	result, _ := new(big.Int).SetString(s, 10)
+	return result
+}
@@ -1,22 +1,55 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
 )
 
-func errrorJson(statusCode int, message string, writer http.ResponseWriter) {
+func errrorJson(c echo.Context, statusCode int, message string) {
+	writer := c.Response().Writer
 	writer.WriteHeader(statusCode)
 	resp, err := json.Marshal(&domain.CommonResponse{
 		Message: message,
 		Status:  false,
 	})
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c.Request().Context(), "failed to encode error response", "error", err)
 	}
 
 	writer.Write(resp)
 }
+
+// setRetryAfter advertises when a request rejected by a tripped dependency
+// may be retried.
+func setRetryAfter(c echo.Context, err error) {
+	var retryAfter *domain.RetryAfterError
+	if errors.As(err, &retryAfter) {
+		seconds := int(math.Ceil(retryAfter.RetryAfter.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	}
+}
+
+func estimateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, domain.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrPriceDeviation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrQuorumNotReached):
+		return http.StatusBadGateway
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
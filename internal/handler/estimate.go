@@ -1,38 +1,88 @@
 package handler
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/DiDinar5/1inch_test_task/domain"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+var tracer = otel.Tracer("github.com/DiDinar5/1inch_test_task/internal/handler")
+
 func (h *Handler) EstimateHandler(c echo.Context) error {
-	var req domain.EstimateRequest
+	ctx, span := tracer.Start(c.Request().Context(), "EstimateHandler")
+	defer span.End()
+	c.SetRequest(c.Request().WithContext(ctx))
 
-	if err := echo.QueryParamsBinder(c).
-		String("pool", &req.Pool).
-		String("src", &req.Src).
-		String("dst", &req.Dst).
-		String("src_amount", &req.SrcAmount).
-		BindError(); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
+	chain, usecase, ok := h.chainUsecase(c)
+	if !ok {
 		return nil
 	}
 
-	if err := c.Validate(&req); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
+	req, err := bindEstimateRequest(c)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errrorJson(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	span.SetAttributes(
+		attribute.String("chain", chain),
+		attribute.String("pool.address", req.Pool),
+		attribute.String("token.src", req.Src),
+		attribute.String("token.dst", req.Dst),
+		attribute.Bool("quorum", req.Quorum),
+	)
+
+	ctx, cancel, err := h.requestContext(c, chain)
+	if err != nil {
+		errrorJson(c, http.StatusBadRequest, err.Error())
 		return nil
 	}
+	defer cancel()
 
-	response, err := h.usecase.Estimate(c.Request().Context(), req)
+	response, err := usecase.Estimate(ctx, req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		statusCode := estimateErrorStatus(err)
+		if statusCode >= http.StatusInternalServerError {
+			slog.ErrorContext(ctx, "estimate failed", "chain", chain, "pool", req.Pool, "status", statusCode, "error", err)
+		}
+		setRetryAfter(c, err)
+		return c.JSON(statusCode, domain.ErrorResponse{
 			Error:       "Estimation failed",
-			Code:        http.StatusInternalServerError,
+			Code:        statusCode,
 			Description: err.Error(),
 		})
 	}
 
+	span.SetAttributes(attribute.Int64("block.number", int64(response.BlockNumber)))
+
 	return c.JSON(http.StatusOK, response)
 }
+
+func bindEstimateRequest(c echo.Context) (domain.EstimateRequest, error) {
+	var req domain.EstimateRequest
+
+	if err := echo.QueryParamsBinder(c).
+		String("pool", &req.Pool).
+		String("src", &req.Src).
+		String("dst", &req.Dst).
+		String("src_amount", &req.SrcAmount).
+		Bool("strict", &req.Strict).
+		Bool("quorum", &req.Quorum).
+		BindError(); err != nil {
+		return req, err
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
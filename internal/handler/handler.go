@@ -1,20 +1,107 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	usecase domain.UsecaseInterface
+	chains          map[string]domain.UsecaseInterface
+	defaultChain    string
+	requestTimeouts map[string]time.Duration
+	upgrader        websocket.Upgrader
+	sseHeartbeat    time.Duration
+	wsPingInterval  time.Duration
+	draining        atomic.Bool
+}
+
+type Option func(*Handler)
+
+// WithRequestTimeout sets the overall deadline for a single estimate on chain.
+func WithRequestTimeout(chain string, timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.requestTimeouts[strings.ToLower(chain)] = timeout
+	}
 }
 
-func NewHandler(usecase domain.UsecaseInterface) *Handler {
-	return &Handler{
-		usecase: usecase,
+// WithAllowedOrigins lets browsers on other origins open websocket streams.
+// Origins are matched as scheme://host[:port]; "*" allows any origin. Without
+// this option only same-origin and non-browser clients are accepted.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *Handler) {
+		h.upgrader.CheckOrigin = checkOrigin(origins)
 	}
 }
 
+// NewHandler serves one usecase per chain, keyed by the name used in request
+// paths. Requests that do not name a chain go to defaultChain.
+func NewHandler(chains map[string]domain.UsecaseInterface, defaultChain string, opts ...Option) *Handler {
+	h := &Handler{
+		chains:          make(map[string]domain.UsecaseInterface, len(chains)),
+		defaultChain:    strings.ToLower(defaultChain),
+		requestTimeouts: make(map[string]time.Duration),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		sseHeartbeat:   sseHeartbeatInterval,
+		wsPingInterval: wsPingInterval,
+	}
+
+	for name, usecase := range chains {
+		h.chains[strings.ToLower(name)] = usecase
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// SetupRoutes serves every chain-scoped route both under /:chain and without
+// a prefix, where the chain comes from ?chain= or falls back to the default.
 func (h *Handler) SetupRoutes(e *echo.Echo) {
-	e.GET("/estimate", h.EstimateHandler)
+	for _, prefix := range []string{"", "/:chain"} {
+		g := e.Group(prefix)
+		g.GET("/estimate", h.EstimateHandler)
+		g.GET("/estimate/stream", h.EstimateStreamHandler)
+		g.GET("/estimate/ws", h.EstimateWebSocketHandler)
+		g.GET("/twap", h.TWAPHandler)
+		g.GET("/status", h.StatusHandler)
+		g.GET("/status/providers", h.ProviderStatusHandler)
+		g.GET("/readyz", h.ReadyzHandler)
+	}
+	e.GET("/healthz", h.HealthzHandler)
+}
+
+func chainName(c echo.Context) string {
+	if name := c.Param("chain"); name != "" {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(strings.TrimSpace(c.QueryParam("chain")))
+}
+
+// chainUsecase resolves the chain a request is for and writes a 404 when it
+// is not configured.
+func (h *Handler) chainUsecase(c echo.Context) (string, domain.UsecaseInterface, bool) {
+	name := chainName(c)
+	if name == "" {
+		name = h.defaultChain
+	}
+
+	usecase, ok := h.chains[name]
+	if !ok {
+		errrorJson(c, http.StatusNotFound, fmt.Sprintf("unknown chain: %s", name))
+		return "", nil, false
+	}
+
+	return name, usecase, true
 }
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/DiDinar5/1inch_test_task/internal/middlewares"
+	"github.com/labstack/echo/v4"
+)
+
+// mockUsecase streams whatever is pushed to a pool's feed and reports on
+// stopped when a watch ends.
+type mockUsecase struct {
+	mu        sync.Mutex
+	feeds     map[string]chan domain.EstimateResponse
+	stopped   chan string
+	watchErr  error
+	readiness domain.Readiness
+	// release, when set, holds WatchEstimate until it is closed.
+	release chan struct{}
+}
+
+func newMockUsecase() *mockUsecase {
+	return &mockUsecase{
+		feeds:     make(map[string]chan domain.EstimateResponse),
+		stopped:   make(chan string, 16),
+		readiness: domain.Readiness{Ready: true},
+	}
+}
+
+func (m *mockUsecase) feed(pool string) chan domain.EstimateResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	feed, ok := m.feeds[pool]
+	if !ok {
+		feed = make(chan domain.EstimateResponse, 16)
+		m.feeds[pool] = feed
+	}
+	return feed
+}
+
+func (m *mockUsecase) Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
+	return domain.EstimateResponse{}, nil
+}
+
+func (m *mockUsecase) WatchEstimate(ctx context.Context, req domain.EstimateRequest) (<-chan domain.EstimateResponse, error) {
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if m.watchErr != nil {
+		return nil, m.watchErr
+	}
+
+	feed := m.feed(req.Pool)
+	updates := make(chan domain.EstimateResponse)
+	go func() {
+		defer close(updates)
+		defer func() { m.stopped <- req.Pool }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case response := <-feed:
+				select {
+				case updates <- response:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func (m *mockUsecase) ChainStatus(ctx context.Context) domain.ChainStatus {
+	return domain.ChainStatus{}
+}
+
+func (m *mockUsecase) TWAP(ctx context.Context, req domain.TWAPRequest) (domain.TWAPResponse, error) {
+	return domain.TWAPResponse{}, nil
+}
+
+func (m *mockUsecase) ProviderStatus(ctx context.Context) []domain.ProviderStatus {
+	return nil
+}
+
+func (m *mockUsecase) Readiness(ctx context.Context) domain.Readiness {
+	return m.readiness
+}
+
+func newTestEcho(h *Handler) *echo.Echo {
+	e := echo.New()
+	e.Validator = middlewares.NewValidator()
+	h.SetupRoutes(e)
+	return e
+}
+
+func newTestServer(t *testing.T, h *Handler) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(newTestEcho(h))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const readinessTimeout = 3 * time.Second
+
+// Drain marks the service as not ready so load balancers stop routing to it
+// while in-flight requests finish.
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// HealthzHandler reports liveness: the process is up and serving HTTP.
+func (h *Handler) HealthzHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ReadyzHandler reports whether the service can quote right now. Without a
+// chain in the path or query every chain must be ready, and each check is
+// prefixed with its chain name.
+func (h *Handler) ReadyzHandler(c echo.Context) error {
+	if h.draining.Load() {
+		return c.JSON(http.StatusServiceUnavailable, domain.Readiness{
+			Checks: []domain.HealthCheck{{Name: "shutdown", Detail: "server is shutting down"}},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	var readiness domain.Readiness
+	if chainName(c) != "" {
+		_, usecase, ok := h.chainUsecase(c)
+		if !ok {
+			return nil
+		}
+		readiness = usecase.Readiness(ctx)
+	} else {
+		readiness = h.allChainsReadiness(ctx)
+	}
+
+	if !readiness.Ready {
+		return c.JSON(http.StatusServiceUnavailable, readiness)
+	}
+
+	return c.JSON(http.StatusOK, readiness)
+}
+
+func (h *Handler) allChainsReadiness(ctx context.Context) domain.Readiness {
+	names := make([]string, 0, len(h.chains))
+	for name := range h.chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Chains are checked in parallel so one slow RPC does not eat the
+	// others' share of the readiness timeout.
+	results := make([]domain.Readiness, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.chains[name].Readiness(ctx)
+		}()
+	}
+	wg.Wait()
+
+	readiness := domain.Readiness{Ready: len(names) > 0}
+	for i, chain := range results {
+		readiness.Ready = readiness.Ready && chain.Ready
+		for _, check := range chain.Checks {
+			check.Name = names[i] + "/" + check.Name
+			readiness.Checks = append(readiness.Checks, check)
+		}
+	}
+
+	return readiness
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestReadyz(t *testing.T) {
+	ready := domain.Readiness{Ready: true, Checks: []domain.HealthCheck{{Name: "head", Healthy: true}}}
+	notReady := domain.Readiness{Checks: []domain.HealthCheck{{Name: "head", Detail: "head is 5m old"}}}
+
+	tests := []struct {
+		name           string
+		path           string
+		draining       bool
+		bsc            domain.Readiness
+		expectedStatus int
+		expectedChecks []string
+	}{
+		{
+			name:           "Every chain ready",
+			path:           "/readyz",
+			bsc:            ready,
+			expectedStatus: http.StatusOK,
+			expectedChecks: []string{"bsc/head", "ethereum/head"},
+		},
+		{
+			name:           "One chain not ready",
+			path:           "/readyz",
+			bsc:            notReady,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: []string{"bsc/head", "ethereum/head"},
+		},
+		{
+			name:           "Ready chain by path",
+			path:           "/ethereum/readyz",
+			bsc:            notReady,
+			expectedStatus: http.StatusOK,
+			expectedChecks: []string{"head"},
+		},
+		{
+			name:           "Failing chain by query",
+			path:           "/readyz?chain=bsc",
+			bsc:            notReady,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: []string{"head"},
+		},
+		{
+			name:           "Unknown chain",
+			path:           "/polygon/readyz",
+			bsc:            ready,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Draining",
+			path:           "/readyz",
+			draining:       true,
+			bsc:            ready,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: []string{"shutdown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ethereum := newMockUsecase()
+			ethereum.readiness = ready
+			bsc := newMockUsecase()
+			bsc.readiness = tt.bsc
+
+			h := NewHandler(map[string]domain.UsecaseInterface{"ethereum": ethereum, "bsc": bsc}, "ethereum")
+			if tt.draining {
+				h.Drain()
+			}
+
+			recorder := httptest.NewRecorder()
+			newTestEcho(h).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body)
+			}
+			if tt.expectedChecks == nil {
+				return
+			}
+
+			var readiness domain.Readiness
+			if err := json.Unmarshal(recorder.Body.Bytes(), &readiness); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if readiness.Ready != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("Expected ready %v, got %+v", tt.expectedStatus == http.StatusOK, readiness)
+			}
+			if len(readiness.Checks) != len(tt.expectedChecks) {
+				t.Fatalf("Expected checks %v, got %+v", tt.expectedChecks, readiness.Checks)
+			}
+			for i, name := range tt.expectedChecks {
+				if readiness.Checks[i].Name != name {
+					t.Errorf("Expected check %d to be %s, got %s", i, name, readiness.Checks[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthzIgnoresDraining(t *testing.T) {
+	h := NewHandler(map[string]domain.UsecaseInterface{"ethereum": newMockUsecase()}, "ethereum")
+	h.Drain()
+
+	recorder := httptest.NewRecorder()
+	newTestEcho(h).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected liveness to stay up while draining, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetry             = 3 * time.Second
+)
+
+// EstimateStreamHandler pushes an estimate event whenever the pool's state
+// changes. Event IDs are block numbers: a client resuming with Last-Event-ID
+// is sent the current state immediately unless it already has that block.
+func (h *Handler) EstimateStreamHandler(c echo.Context) error {
+	_, usecase, ok := h.chainUsecase(c)
+	if !ok {
+		return nil
+	}
+
+	req, err := bindEstimateRequest(c)
+	if err != nil {
+		errrorJson(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	var lastEventID uint64
+	if header := c.Request().Header.Get("Last-Event-ID"); header != "" {
+		lastEventID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			errrorJson(c, http.StatusBadRequest, "invalid Last-Event-ID")
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	updates, err := usecase.WatchEstimate(ctx, req)
+	if err != nil {
+		statusCode := estimateErrorStatus(err)
+		return c.JSON(statusCode, domain.ErrorResponse{
+			Error:       "Estimation failed",
+			Code:        statusCode,
+			Description: err.Error(),
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case response, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if response.BlockNumber <= lastEventID {
+				lastEventID = 0
+				continue
+			}
+			lastEventID = 0
+
+			if err := writeEstimateEvent(res, response); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+func writeEstimateEvent(res *echo.Response, response domain.EstimateResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", response.BlockNumber, domain.StreamEstimate, data)
+	return err
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+const streamQuery = "/estimate/stream?pool=0xpool&src=0xsrc&dst=0xdst&src_amount=1000"
+
+type sseEvent struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+// openStream starts an SSE request and returns its events as they arrive.
+func openStream(t *testing.T, ctx context.Context, url, lastEventID string) (*http.Response, <-chan sseEvent) {
+	t.Helper()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url+streamQuery, nil)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer close(events)
+
+		scanner := bufio.NewScanner(response.Body)
+		var event sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				events <- event
+				event = sseEvent{}
+			case strings.HasPrefix(line, ":"):
+				event.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				event.id = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				event.event = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				event.data = line[len("data: "):]
+			}
+		}
+	}()
+
+	return response, events
+}
+
+// nextEvent skips heartbeats and the retry preamble unless keepComments is set.
+func nextEvent(t *testing.T, events <-chan sseEvent, keepComments bool) sseEvent {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("Stream closed unexpectedly")
+			}
+			if event.event == "" && !keepComments {
+				continue
+			}
+			if event.event == "" && event.comment == "" {
+				continue
+			}
+			return event
+		case <-timeout:
+			t.Fatalf("Expected an event")
+		}
+	}
+}
+
+func TestEstimateStream(t *testing.T) {
+	usecase := newMockUsecase()
+	server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": usecase}, "ethereum"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	response, events := openStream(t, ctx, server.URL, "")
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+
+	usecase.feed("0xpool") <- domain.EstimateResponse{DstAmount: "42", BlockNumber: 17}
+	event := nextEvent(t, events, false)
+	if event.id != "17" || event.event != domain.StreamEstimate {
+		t.Errorf("Expected event id 17 of type estimate, got %+v", event)
+	}
+
+	var estimate domain.EstimateResponse
+	if err := json.Unmarshal([]byte(event.data), &estimate); err != nil || estimate.DstAmount != "42" {
+		t.Errorf("Expected the estimate as JSON data, got %q (%v)", event.data, err)
+	}
+
+	cancel()
+	select {
+	case <-usecase.stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the watch to stop when the client disconnects")
+	}
+}
+
+func TestEstimateStreamResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		blocks      []uint64
+		expected    string
+	}{
+		{name: "Fresh client gets the current block", blocks: []uint64{10, 11}, expected: "10"},
+		{name: "Already seen block is skipped", lastEventID: "10", blocks: []uint64{10, 11}, expected: "11"},
+		{name: "Newer block is sent at once", lastEventID: "10", blocks: []uint64{12}, expected: "12"},
+		{name: "Only the first update is compared", lastEventID: "10", blocks: []uint64{9, 8}, expected: "8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := newMockUsecase()
+			server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": usecase}, "ethereum"))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, events := openStream(t, ctx, server.URL, tt.lastEventID)
+			for _, block := range tt.blocks {
+				usecase.feed("0xpool") <- domain.EstimateResponse{BlockNumber: block}
+			}
+
+			if event := nextEvent(t, events, false); event.id != tt.expected {
+				t.Errorf("Expected event id %s, got %s", tt.expected, event.id)
+			}
+		})
+	}
+}
+
+func TestEstimateStreamInvalidLastEventID(t *testing.T) {
+	server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": newMockUsecase()}, "ethereum"))
+
+	response, _ := openStream(t, context.Background(), server.URL, "block-10")
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed Last-Event-ID, got %d", response.StatusCode)
+	}
+}
+
+func TestEstimateStreamHeartbeat(t *testing.T) {
+	h := NewHandler(map[string]domain.UsecaseInterface{"ethereum": newMockUsecase()}, "ethereum")
+	h.sseHeartbeat = 20 * time.Millisecond
+	server := newTestServer(t, h)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, events := openStream(t, ctx, server.URL, "")
+	for range 2 {
+		if event := nextEvent(t, events, true); event.comment != "ping" {
+			t.Fatalf("Expected a ping comment, got %+v", event)
+		}
+	}
+}
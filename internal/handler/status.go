@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) StatusHandler(c echo.Context) error {
+	_, usecase, ok := h.chainUsecase(c)
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, usecase.ChainStatus(c.Request().Context()))
+}
+
+func (h *Handler) ProviderStatusHandler(c echo.Context) error {
+	_, usecase, ok := h.chainUsecase(c)
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, usecase.ProviderStatus(c.Request().Context()))
+}
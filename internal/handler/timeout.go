@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// requestContext bounds a request by the configured deadline. Clients may
+// shorten it with X-Request-Timeout, given as a duration ("750ms") or in
+// milliseconds, but never extend it.
+func (h *Handler) requestContext(c echo.Context, chain string) (context.Context, context.CancelFunc, error) {
+	timeout := h.requestTimeouts[chain]
+
+	if header := strings.TrimSpace(c.Request().Header.Get(requestTimeoutHeader)); header != "" {
+		requested, err := parseRequestTimeout(header)
+		if err != nil {
+			return nil, nil, err
+		}
+		if timeout <= 0 || requested < timeout {
+			timeout = requested
+		}
+	}
+
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		return ctx, cancel, nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	return ctx, cancel, nil
+}
+
+func parseRequestTimeout(value string) (time.Duration, error) {
+	var timeout time.Duration
+	if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+		timeout = time.Duration(ms) * time.Millisecond
+	} else if timeout, err = time.ParseDuration(value); err != nil {
+		return 0, fmt.Errorf("invalid %s header: %s", requestTimeoutHeader, value)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s header: must be positive", requestTimeoutHeader)
+	}
+
+	return timeout, nil
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{name: "Milliseconds", value: "750", expected: 750 * time.Millisecond},
+		{name: "Duration", value: "1.5s", expected: 1500 * time.Millisecond},
+		{name: "Duration in milliseconds", value: "250ms", expected: 250 * time.Millisecond},
+		{name: "Zero milliseconds", value: "0", expectError: true},
+		{name: "Zero duration", value: "0s", expectError: true},
+		{name: "Negative milliseconds", value: "-100", expectError: true},
+		{name: "Negative duration", value: "-1s", expectError: true},
+		{name: "Garbage", value: "soon", expectError: true},
+		{name: "Milliseconds overflow", value: "99999999999", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseRequestTimeout(tt.value)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %s", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		configured  time.Duration
+		header      string
+		expected    time.Duration
+		expectError bool
+	}{
+		{name: "Configured timeout", configured: 5 * time.Second, expected: 5 * time.Second},
+		{name: "Header shortens the timeout", configured: 5 * time.Second, header: "500", expected: 500 * time.Millisecond},
+		{name: "Header cannot extend the timeout", configured: 5 * time.Second, header: "1m", expected: 5 * time.Second},
+		{name: "Header applies without a configured timeout", header: "2s", expected: 2 * time.Second},
+		{name: "No deadline at all"},
+		{name: "Invalid header", configured: 5 * time.Second, header: "-1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]domain.UsecaseInterface{}, "ethereum", WithRequestTimeout("ethereum", tt.configured))
+
+			request := httptest.NewRequest(http.MethodGet, "/estimate", nil)
+			if tt.header != "" {
+				request.Header.Set(requestTimeoutHeader, tt.header)
+			}
+			c := echo.New().NewContext(request, httptest.NewRecorder())
+
+			ctx, cancel, err := h.requestContext(c, "ethereum")
+
+			if tt.expectError {
+				if err == nil {
+					cancel()
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if tt.expected == 0 {
+				if ok {
+					t.Errorf("Expected no deadline, got %s", time.Until(deadline))
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("Expected a deadline of %s", tt.expected)
+			}
+			if remaining := time.Until(deadline); remaining < tt.expected-100*time.Millisecond || remaining > tt.expected {
+				t.Errorf("Expected a deadline of %s, got %s", tt.expected, remaining)
+			}
+		})
+	}
+}
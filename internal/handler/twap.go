@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) TWAPHandler(c echo.Context) error {
+	chain, usecase, ok := h.chainUsecase(c)
+	if !ok {
+		return nil
+	}
+
+	var req domain.TWAPRequest
+
+	if err := echo.QueryParamsBinder(c).
+		String("pool", &req.Pool).
+		String("window", &req.Window).
+		BindError(); err != nil {
+		errrorJson(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	if err := c.Validate(&req); err != nil {
+		errrorJson(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	ctx, cancel, err := h.requestContext(c, chain)
+	if err != nil {
+		errrorJson(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+	defer cancel()
+
+	response, err := usecase.TWAP(ctx, req)
+	if err != nil {
+		statusCode := estimateErrorStatus(err)
+		setRetryAfter(c, err)
+		return c.JSON(statusCode, domain.ErrorResponse{
+			Error:       "TWAP calculation failed",
+			Code:        statusCode,
+			Description: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
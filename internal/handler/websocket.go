@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	wsWriteWait        = 10 * time.Second
+	wsPongWait         = 60 * time.Second
+	wsPingInterval     = 30 * time.Second
+	wsMaxMessageSize   = 4096
+	wsMaxSubscriptions = 32
+	wsMaxQueuedControl = 64
+)
+
+// checkOrigin accepts requests without an Origin header, same-origin requests
+// and the listed origins.
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	origins := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		origins[strings.ToLower(strings.TrimRight(origin, "/"))] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || origins["*"] || origins[strings.ToLower(origin)] {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// wsSession multiplexes estimate subscriptions over a single connection.
+// Pending estimates are coalesced per subscription so a slow consumer only
+// receives the latest quote; a backlog of control messages closes the session.
+type wsSession struct {
+	conn         *websocket.Conn
+	echo         echo.Context
+	ctx          context.Context
+	cancel       context.CancelFunc
+	pingInterval time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	control       []domain.StreamMessage
+	estimates     map[string]domain.StreamMessage
+	wake          chan struct{}
+}
+
+func (h *Handler) EstimateWebSocketHandler(c echo.Context) error {
+	_, usecase, ok := h.chainUsecase(c)
+	if !ok {
+		return nil
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	session := &wsSession{
+		conn:          conn,
+		echo:          c,
+		ctx:           ctx,
+		cancel:        cancel,
+		pingInterval:  h.wsPingInterval,
+		subscriptions: make(map[string]context.CancelFunc),
+		estimates:     make(map[string]domain.StreamMessage),
+		wake:          make(chan struct{}, 1),
+	}
+
+	go session.writeLoop()
+	session.readLoop(usecase)
+
+	return nil
+}
+
+func (s *wsSession) readLoop(usecase domain.UsecaseInterface) {
+	defer s.cancel()
+
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req domain.StreamRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.sendControl(domain.StreamMessage{Type: domain.StreamError, Error: fmt.Sprintf("invalid message: %v", err)})
+			continue
+		}
+
+		if err := s.echo.Validate(&req); err != nil {
+			s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: req.ID, Error: err.Error()})
+			continue
+		}
+
+		switch req.Type {
+		case domain.StreamSubscribe:
+			s.subscribe(usecase, req)
+		case domain.StreamUnsubscribe:
+			s.unsubscribe(req.ID)
+		}
+	}
+}
+
+func (s *wsSession) subscribe(usecase domain.UsecaseInterface, req domain.StreamRequest) {
+	if err := s.echo.Validate(&req.EstimateRequest); err != nil {
+		s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: req.ID, Error: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if _, exists := s.subscriptions[req.ID]; exists {
+		s.mu.Unlock()
+		s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: req.ID, Error: "subscription already exists"})
+		return
+	}
+	if len(s.subscriptions) >= wsMaxSubscriptions {
+		s.mu.Unlock()
+		s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: req.ID, Error: "too many subscriptions"})
+		return
+	}
+	subCtx, subCancel := context.WithCancel(s.ctx)
+	s.subscriptions[req.ID] = subCancel
+	// Queued with the registration so it always precedes an "unsubscribed";
+	// a failing watch is reported as an error afterwards.
+	queued := s.queueControl(domain.StreamMessage{Type: domain.StreamSubscribed, ID: req.ID})
+	s.mu.Unlock()
+
+	if !queued {
+		s.closeSlow()
+		return
+	}
+	s.notify()
+
+	go func() {
+		updates, err := usecase.WatchEstimate(subCtx, req.EstimateRequest)
+		if err != nil {
+			if subCtx.Err() != nil {
+				return
+			}
+			s.removeSubscription(req.ID)
+			s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: req.ID, Error: err.Error()})
+			return
+		}
+
+		for response := range updates {
+			s.sendEstimate(req.ID, response)
+		}
+	}()
+}
+
+func (s *wsSession) unsubscribe(id string) {
+	if !s.removeSubscription(id) {
+		s.sendControl(domain.StreamMessage{Type: domain.StreamError, ID: id, Error: "unknown subscription"})
+		return
+	}
+
+	s.sendControl(domain.StreamMessage{Type: domain.StreamUnsubscribed, ID: id})
+}
+
+func (s *wsSession) removeSubscription(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cancel, exists := s.subscriptions[id]
+	if !exists {
+		return false
+	}
+
+	cancel()
+	delete(s.subscriptions, id)
+	delete(s.estimates, id)
+
+	return true
+}
+
+func (s *wsSession) sendControl(msg domain.StreamMessage) {
+	s.mu.Lock()
+	queued := s.queueControl(msg)
+	s.mu.Unlock()
+
+	if !queued {
+		s.closeSlow()
+		return
+	}
+	s.notify()
+}
+
+// queueControl must be called with s.mu held.
+func (s *wsSession) queueControl(msg domain.StreamMessage) bool {
+	if len(s.control) >= wsMaxQueuedControl {
+		return false
+	}
+	s.control = append(s.control, msg)
+	return true
+}
+
+func (s *wsSession) closeSlow() {
+	slog.WarnContext(s.ctx, "closing websocket session: slow consumer")
+	s.cancel()
+}
+
+func (s *wsSession) sendEstimate(id string, response domain.EstimateResponse) {
+	s.mu.Lock()
+	if _, active := s.subscriptions[id]; !active {
+		s.mu.Unlock()
+		return
+	}
+	s.estimates[id] = domain.StreamMessage{Type: domain.StreamEstimate, ID: id, Data: &response}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+func (s *wsSession) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *wsSession) writeLoop() {
+	ticker := time.NewTicker(s.pingInterval)
+	defer func() {
+		ticker.Stop()
+		s.cancel()
+		s.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+		s.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-s.wake:
+			for _, msg := range s.drain() {
+				s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := s.conn.WriteJSON(msg); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *wsSession) drain() []domain.StreamMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]domain.StreamMessage, 0, len(s.control)+len(s.estimates))
+	messages = append(messages, s.control...)
+	for _, msg := range s.estimates {
+		messages = append(messages, msg)
+	}
+
+	s.control = s.control[:0]
+	clear(s.estimates)
+
+	return messages
+}
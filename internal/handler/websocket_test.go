@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/gorilla/websocket"
+)
+
+func dialWebSocket(t *testing.T, url string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/estimate/ws", header)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, response, err
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) domain.StreamMessage {
+	t.Helper()
+
+	var msg domain.StreamMessage
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	return msg
+}
+
+func subscribeRequest(id, pool string) domain.StreamRequest {
+	return domain.StreamRequest{
+		Type: domain.StreamSubscribe,
+		ID:   id,
+		EstimateRequest: domain.EstimateRequest{
+			Pool:      pool,
+			Src:       "0xsrc",
+			Dst:       "0xdst",
+			SrcAmount: "1000",
+		},
+	}
+}
+
+func waitStopped(t *testing.T, usecase *mockUsecase, pool string) {
+	t.Helper()
+
+	select {
+	case stopped := <-usecase.stopped:
+		if stopped != pool {
+			t.Errorf("Expected the watch on %s to stop, got %s", pool, stopped)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the watch on %s to stop", pool)
+	}
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	usecase := newMockUsecase()
+	server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": usecase}, "ethereum"))
+
+	conn, _, err := dialWebSocket(t, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+
+	for _, req := range []domain.StreamRequest{subscribeRequest("a", "0xpoolA"), subscribeRequest("b", "0xpoolB")} {
+		if err := conn.WriteJSON(req); err != nil {
+			t.Fatalf("Failed to subscribe: %v", err)
+		}
+	}
+
+	subscribed := map[string]bool{}
+	for range 2 {
+		msg := readStreamMessage(t, conn)
+		if msg.Type != domain.StreamSubscribed {
+			t.Fatalf("Expected subscribed, got %+v", msg)
+		}
+		subscribed[msg.ID] = true
+	}
+	if !subscribed["a"] || !subscribed["b"] {
+		t.Fatalf("Expected both subscriptions to be confirmed, got %v", subscribed)
+	}
+
+	usecase.feed("0xpoolA") <- domain.EstimateResponse{DstAmount: "1", BlockNumber: 10}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamEstimate || msg.ID != "a" || msg.Data.DstAmount != "1" {
+		t.Errorf("Expected an estimate for a, got %+v", msg)
+	}
+	usecase.feed("0xpoolB") <- domain.EstimateResponse{DstAmount: "2", BlockNumber: 10}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamEstimate || msg.ID != "b" || msg.Data.DstAmount != "2" {
+		t.Errorf("Expected an estimate for b, got %+v", msg)
+	}
+
+	if err := conn.WriteJSON(subscribeRequest("a", "0xpoolC")); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamError || msg.ID != "a" {
+		t.Errorf("Expected a duplicate subscription error, got %+v", msg)
+	}
+
+	if err := conn.WriteJSON(domain.StreamRequest{Type: domain.StreamUnsubscribe, ID: "a"}); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamUnsubscribed || msg.ID != "a" {
+		t.Errorf("Expected unsubscribed, got %+v", msg)
+	}
+	waitStopped(t, usecase, "0xpoolA")
+
+	if err := conn.WriteJSON(domain.StreamRequest{Type: domain.StreamUnsubscribe, ID: "a"}); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamError || msg.Error != "unknown subscription" {
+		t.Errorf("Expected an unknown subscription error, got %+v", msg)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if msg := readStreamMessage(t, conn); msg.Type != domain.StreamError {
+		t.Errorf("Expected an invalid message error, got %+v", msg)
+	}
+
+	conn.Close()
+	waitStopped(t, usecase, "0xpoolB")
+}
+
+func TestWebSocketAcknowledgementOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		watchErr error
+		request  domain.StreamRequest
+		expected []string
+	}{
+		{
+			name:     "Unsubscribe before the watch starts",
+			request:  domain.StreamRequest{Type: domain.StreamUnsubscribe, ID: "a"},
+			expected: []string{domain.StreamSubscribed, domain.StreamUnsubscribed},
+		},
+		{
+			name:     "Failing watch",
+			watchErr: errors.New("pool not found"),
+			expected: []string{domain.StreamSubscribed, domain.StreamError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := newMockUsecase()
+			usecase.release = make(chan struct{})
+			usecase.watchErr = tt.watchErr
+			server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": usecase}, "ethereum"))
+
+			conn, _, err := dialWebSocket(t, server.URL, nil)
+			if err != nil {
+				t.Fatalf("Failed to dial: %v", err)
+			}
+
+			if err := conn.WriteJSON(subscribeRequest("a", "0xpoolA")); err != nil {
+				t.Fatalf("Failed to subscribe: %v", err)
+			}
+			if tt.request.Type != "" {
+				if err := conn.WriteJSON(tt.request); err != nil {
+					t.Fatalf("Failed to write: %v", err)
+				}
+			}
+			if msg := readStreamMessage(t, conn); msg.Type != tt.expected[0] {
+				t.Fatalf("Expected %s first, got %+v", tt.expected[0], msg)
+			}
+			close(usecase.release)
+
+			for _, expected := range tt.expected[1:] {
+				if msg := readStreamMessage(t, conn); msg.Type != expected || msg.ID != "a" {
+					t.Errorf("Expected %s for a, got %+v", expected, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestWebSocketHeartbeat(t *testing.T) {
+	h := NewHandler(map[string]domain.UsecaseInterface{"ethereum": newMockUsecase()}, "ethereum")
+	h.wsPingInterval = 20 * time.Millisecond
+	server := newTestServer(t, h)
+
+	conn, _, err := dialWebSocket(t, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+
+	pings := make(chan struct{}, 8)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for range 2 {
+		select {
+		case <-pings:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected periodic pings")
+		}
+	}
+}
+
+func TestWebSocketSlowConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := &wsSession{
+		ctx:           ctx,
+		cancel:        cancel,
+		subscriptions: map[string]context.CancelFunc{"a": func() {}},
+		estimates:     make(map[string]domain.StreamMessage),
+		wake:          make(chan struct{}, 1),
+	}
+
+	for block := uint64(1); block <= 100; block++ {
+		session.sendEstimate("a", domain.EstimateResponse{BlockNumber: block})
+	}
+	session.sendEstimate("gone", domain.EstimateResponse{BlockNumber: 1})
+
+	messages := session.drain()
+	if len(messages) != 1 || messages[0].ID != "a" || messages[0].Data.BlockNumber != 100 {
+		t.Fatalf("Expected only the latest estimate to be queued, got %+v", messages)
+	}
+
+	for range wsMaxQueuedControl {
+		session.sendControl(domain.StreamMessage{Type: domain.StreamError})
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("Expected the session to survive a full control queue")
+	}
+
+	session.sendControl(domain.StreamMessage{Type: domain.StreamError})
+	if ctx.Err() == nil {
+		t.Errorf("Expected a control backlog to close the session")
+	}
+}
+
+func TestWebSocketAllowedOrigins(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		origin   string
+		expected bool
+	}{
+		{name: "No origin header", origin: "", expected: true},
+		{name: "Cross origin rejected by default", origin: "https://app.example.com", expected: false},
+		{name: "Listed origin", allowed: []string{"https://app.example.com/"}, origin: "https://APP.example.com", expected: true},
+		{name: "Unlisted origin", allowed: []string{"https://app.example.com"}, origin: "https://evil.example.com", expected: false},
+		{name: "Wildcard", allowed: []string{"*"}, origin: "https://evil.example.com", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.allowed != nil {
+				opts = append(opts, WithAllowedOrigins(tt.allowed...))
+			}
+			server := newTestServer(t, NewHandler(map[string]domain.UsecaseInterface{"ethereum": newMockUsecase()}, "ethereum", opts...))
+
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			_, response, err := dialWebSocket(t, server.URL, header)
+
+			if tt.expected && err != nil {
+				t.Errorf("Expected the upgrade to succeed, got %v", err)
+			}
+			if !tt.expected && (err == nil || response.StatusCode != http.StatusForbidden) {
+				t.Errorf("Expected the upgrade to be forbidden, got %v", err)
+			}
+		})
+	}
+}
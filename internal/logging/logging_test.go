@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLoggerAddsContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	ctx = domain.WithRequestID(ctx, "req-1")
+
+	logger.With("component", "test").InfoContext(ctx, "quote served", "pool", "0x1234")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]string{
+		"msg":        "quote served",
+		"pool":       "0x1234",
+		"component":  "test",
+		"request_id": "req-1",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%q, got %v", key, value, record[key])
+		}
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	tests := []struct {
+		level       string
+		expectDebug bool
+		expectError bool
+	}{
+		{level: "", expectDebug: false},
+		{level: "debug", expectDebug: true},
+		{level: "WARN", expectDebug: false},
+		{level: "verbose", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(&buf, tt.level)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for level %q", tt.level)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			logger.Debug("details")
+			if logged := buf.Len() > 0; logged != tt.expectDebug {
+				t.Errorf("Expected debug logged=%v, got %v", tt.expectDebug, logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "estimator"
+
+// Metrics owns the Prometheus registry and the collectors fed by the handler,
+// usecase and ethereum layers.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+	rpcCalls     *prometheus.CounterVec
+	rpcDuration  *prometheus.HistogramVec
+	quotes       *prometheus.CounterVec
+
+	sourcesMu      sync.RWMutex
+	cacheSources   map[string]func() map[string]domain.CacheStats
+	breakerSources map[string]func() *domain.BreakerStatus
+	limiterSources map[string]func() []domain.LimiterStats
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry:       prometheus.NewRegistry(),
+		cacheSources:   make(map[string]func() map[string]domain.CacheStats),
+		breakerSources: make(map[string]func() *domain.BreakerStatus),
+		limiterSources: make(map[string]func() []domain.LimiterStats),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+		rpcCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_calls_total",
+			Help:      "RPC calls by chain, method, provider and outcome.",
+		}, []string{"chain", "method", "provider", "outcome"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_call_duration_seconds",
+			Help:      "RPC call latency by chain, method and provider.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"chain", "method", "provider"}),
+		quotes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quotes_total",
+			Help:      "Estimates by chain and outcome.",
+		}, []string{"chain", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.inFlight,
+		m.rpcCalls,
+		m.rpcDuration,
+		m.quotes,
+		&cacheCollector{metrics: m},
+		&breakerCollector{metrics: m},
+		&limiterCollector{metrics: m},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records every request under its route template rather than the
+// raw path, so query strings and addresses do not blow up label cardinality.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			start := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) {
+				status = httpErr.Code
+			} else if err != nil {
+				status = http.StatusInternalServerError
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			labels := prometheus.Labels{
+				"route":  route,
+				"method": c.Request().Method,
+				"status": strconv.Itoa(status),
+			}
+			m.httpRequests.With(labels).Inc()
+			m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// ChainMetrics labels the RPC, quote, cache, breaker and rate limiter metrics of one chain.
+type ChainMetrics struct {
+	metrics *Metrics
+	chain   string
+}
+
+// Chain returns the observer for the named chain.
+func (m *Metrics) Chain(name string) *ChainMetrics {
+	return &ChainMetrics{metrics: m, chain: name}
+}
+
+func (c *ChainMetrics) ObserveRPC(method, provider, outcome string, duration time.Duration) {
+	c.metrics.rpcCalls.WithLabelValues(c.chain, method, provider, outcome).Inc()
+	c.metrics.rpcDuration.WithLabelValues(c.chain, method, provider).Observe(duration.Seconds())
+}
+
+func (c *ChainMetrics) ObserveQuote(outcome string) {
+	c.metrics.quotes.WithLabelValues(c.chain, outcome).Inc()
+}
+
+// RegisterCacheStats exports the cache counters returned by stats on every scrape.
+func (c *ChainMetrics) RegisterCacheStats(stats func() map[string]domain.CacheStats) {
+	c.metrics.sourcesMu.Lock()
+	defer c.metrics.sourcesMu.Unlock()
+
+	c.metrics.cacheSources[c.chain] = stats
+}
+
+// RegisterBreakerStatus exports the circuit breaker state returned by status
+// on every scrape; a nil status means the chain has no breaker.
+func (c *ChainMetrics) RegisterBreakerStatus(status func() *domain.BreakerStatus) {
+	c.metrics.sourcesMu.Lock()
+	defer c.metrics.sourcesMu.Unlock()
+
+	c.metrics.breakerSources[c.chain] = status
+}
+
+// RegisterLimiterStats exports the per-provider rate limiter counters returned
+// by stats on every scrape.
+func (c *ChainMetrics) RegisterLimiterStats(stats func() []domain.LimiterStats) {
+	c.metrics.sourcesMu.Lock()
+	defer c.metrics.sourcesMu.Unlock()
+
+	c.metrics.limiterSources[c.chain] = stats
+}
+
+var (
+	cacheLookupsDesc = prometheus.NewDesc(namespace+"_cache_lookups_total",
+		"Cache lookups by cache and result.", []string{"chain", "cache", "result"}, nil)
+	cacheHitRatioDesc = prometheus.NewDesc(namespace+"_cache_hit_ratio",
+		"Share of lookups answered from the cache, including cached failures.", []string{"chain", "cache"}, nil)
+	cacheEvictionsDesc = prometheus.NewDesc(namespace+"_cache_evictions_total",
+		"Entries evicted to stay within capacity.", []string{"chain", "cache"}, nil)
+	cacheExpirationsDesc = prometheus.NewDesc(namespace+"_cache_expirations_total",
+		"Entries dropped after their TTL.", []string{"chain", "cache"}, nil)
+	cacheEntriesDesc = prometheus.NewDesc(namespace+"_cache_entries",
+		"Entries currently held.", []string{"chain", "cache"}, nil)
+)
+
+// cacheCollector reads every chain's cache stats at scrape time. A single
+// collector is registered because the registry rejects duplicate descriptors.
+type cacheCollector struct {
+	metrics *Metrics
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheLookupsDesc
+	ch <- cacheHitRatioDesc
+	ch <- cacheEvictionsDesc
+	ch <- cacheExpirationsDesc
+	ch <- cacheEntriesDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.sourcesMu.RLock()
+	defer c.metrics.sourcesMu.RUnlock()
+
+	for chain, source := range c.metrics.cacheSources {
+		for name, stats := range source() {
+			ch <- prometheus.MustNewConstMetric(cacheLookupsDesc, prometheus.CounterValue, float64(stats.Hits), chain, name, "hit")
+			ch <- prometheus.MustNewConstMetric(cacheLookupsDesc, prometheus.CounterValue, float64(stats.NegativeHits), chain, name, "negative_hit")
+			ch <- prometheus.MustNewConstMetric(cacheLookupsDesc, prometheus.CounterValue, float64(stats.Misses), chain, name, "miss")
+
+			var ratio float64
+			if lookups := stats.Hits + stats.NegativeHits + stats.Misses; lookups > 0 {
+				ratio = float64(stats.Hits+stats.NegativeHits) / float64(lookups)
+			}
+			ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, ratio, chain, name)
+
+			ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(stats.Evictions), chain, name)
+			ch <- prometheus.MustNewConstMetric(cacheExpirationsDesc, prometheus.CounterValue, float64(stats.Expirations), chain, name)
+			ch <- prometheus.MustNewConstMetric(cacheEntriesDesc, prometheus.GaugeValue, float64(stats.Size), chain, name)
+		}
+	}
+}
+
+var (
+	breakerStateDesc = prometheus.NewDesc(namespace+"_breaker_state",
+		"Circuit breaker state: 0 closed, 1 half-open, 2 open.", []string{"chain"}, nil)
+	breakerOpenedDesc = prometheus.NewDesc(namespace+"_breaker_opened_total",
+		"Times the circuit breaker opened.", []string{"chain"}, nil)
+	breakerRejectedDesc = prometheus.NewDesc(namespace+"_breaker_rejected_total",
+		"Calls rejected while the circuit breaker was open.", []string{"chain"}, nil)
+)
+
+var breakerStates = map[string]float64{
+	"closed":    0,
+	"half_open": 1,
+	"open":      2,
+}
+
+// breakerCollector reads every chain's circuit breaker at scrape time.
+type breakerCollector struct {
+	metrics *Metrics
+}
+
+func (c *breakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- breakerStateDesc
+	ch <- breakerOpenedDesc
+	ch <- breakerRejectedDesc
+}
+
+func (c *breakerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.sourcesMu.RLock()
+	defer c.metrics.sourcesMu.RUnlock()
+
+	for chain, source := range c.metrics.breakerSources {
+		status := source()
+		if status == nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(breakerStateDesc, prometheus.GaugeValue, breakerStates[status.State], chain)
+		ch <- prometheus.MustNewConstMetric(breakerOpenedDesc, prometheus.CounterValue, float64(status.Opened), chain)
+		ch <- prometheus.MustNewConstMetric(breakerRejectedDesc, prometheus.CounterValue, float64(status.Rejected), chain)
+	}
+}
+
+var (
+	limiterThrottledDesc = prometheus.NewDesc(namespace+"_rpc_throttled_total",
+		"RPC calls that waited for the local rate limiter.", []string{"chain", "provider"}, nil)
+	limiterRejectedDesc = prometheus.NewDesc(namespace+"_rpc_rate_limited_total",
+		"RPC calls refused by the local rate limiter instead of waiting.", []string{"chain", "provider"}, nil)
+)
+
+// limiterCollector reads every chain's per-provider rate limiter counters at scrape time.
+type limiterCollector struct {
+	metrics *Metrics
+}
+
+func (c *limiterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- limiterThrottledDesc
+	ch <- limiterRejectedDesc
+}
+
+func (c *limiterCollector) Collect(ch chan<- prometheus.Metric) {
+	c.metrics.sourcesMu.RLock()
+	defer c.metrics.sourcesMu.RUnlock()
+
+	for chain, source := range c.metrics.limiterSources {
+		for _, stats := range source() {
+			ch <- prometheus.MustNewConstMetric(limiterThrottledDesc, prometheus.CounterValue, float64(stats.Throttled), chain, stats.Provider)
+			ch <- prometheus.MustNewConstMetric(limiterRejectedDesc, prometheus.CounterValue, float64(stats.Rejected), chain, stats.Provider)
+		}
+	}
+}
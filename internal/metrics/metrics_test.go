@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func TestMetricsExposition(t *testing.T) {
+	m := New()
+	mainnet := m.Chain("ethereum")
+	mainnet.RegisterCacheStats(func() map[string]domain.CacheStats {
+		return map[string]domain.CacheStats{
+			"token_info": {Size: 2, Capacity: 10, Hits: 3, Misses: 1},
+		}
+	})
+
+	mainnet.RegisterBreakerStatus(func() *domain.BreakerStatus {
+		return &domain.BreakerStatus{State: "open", Opened: 2, Rejected: 7}
+	})
+	m.Chain("bsc").RegisterBreakerStatus(func() *domain.BreakerStatus { return nil })
+
+	mainnet.RegisterLimiterStats(func() []domain.LimiterStats {
+		return []domain.LimiterStats{{Provider: "alchemy", Throttled: 4, Rejected: 1}}
+	})
+
+	e := echo.New()
+	e.Use(m.Middleware())
+	e.GET("/estimate", func(c echo.Context) error {
+		return c.String(http.StatusBadRequest, "bad")
+	})
+
+	for _, path := range []string{"/estimate?pool=0x1", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	mainnet.ObserveRPC("eth_call", "alchemy", "ok", 20*time.Millisecond)
+	mainnet.ObserveQuote("token_not_in_pool")
+	m.Chain("bsc").ObserveQuote("ok")
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	expected := []string{
+		`estimator_http_requests_total{method="GET",route="/estimate",status="400"} 1`,
+		`estimator_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`estimator_http_requests_in_flight 0`,
+		`estimator_rpc_calls_total{chain="ethereum",method="eth_call",outcome="ok",provider="alchemy"} 1`,
+		`estimator_rpc_call_duration_seconds_count{chain="ethereum",method="eth_call",provider="alchemy"} 1`,
+		`estimator_quotes_total{chain="ethereum",outcome="token_not_in_pool"} 1`,
+		`estimator_quotes_total{chain="bsc",outcome="ok"} 1`,
+		`estimator_cache_hit_ratio{cache="token_info",chain="ethereum"} 0.75`,
+		`estimator_cache_entries{cache="token_info",chain="ethereum"} 2`,
+		`estimator_breaker_state{chain="ethereum"} 2`,
+		`estimator_breaker_opened_total{chain="ethereum"} 2`,
+		`estimator_breaker_rejected_total{chain="ethereum"} 7`,
+		`estimator_rpc_throttled_total{chain="ethereum",provider="alchemy"} 4`,
+		`estimator_rpc_rate_limited_total{chain="ethereum",provider="alchemy"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Expected exposition to contain %q", line)
+		}
+	}
+
+	if strings.Contains(string(body), `estimator_breaker_state{chain="bsc"}`) {
+		t.Errorf("Expected no breaker metrics for a chain without a breaker")
+	}
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Caller ID is kept", header: "abc-123", expected: "abc-123"},
+		{name: "Missing ID is generated"},
+		{name: "Control characters are rejected", header: "abc\n{\"level\":\"ERROR\"}"},
+		{name: "Oversized ID is rejected", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+
+			e := echo.New()
+			e.Use(RequestID())
+			e.GET("/", func(c echo.Context) error {
+				seen = domain.RequestIDFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				request.Header.Set(RequestIDHeader, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+
+			echoed := recorder.Header().Get(RequestIDHeader)
+			if seen == "" || echoed != seen {
+				t.Fatalf("Expected the context ID %q to be echoed, got %q", seen, echoed)
+			}
+			if tt.expected != "" && seen != tt.expected {
+				t.Errorf("Expected ID %q, got %q", tt.expected, seen)
+			}
+			if tt.expected == "" && len(seen) != 32 {
+				t.Errorf("Expected a generated 32 character ID, got %q", seen)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 var (
 	feeNumerator   = big.NewInt(UniswapV2FeeNumerator)
 	feeDenominator = big.NewInt(UniswapV2FeeDenominator)
+	bpsDenominator = big.NewInt(FeeBpsDenominator)
 	zeroBig        = big.NewInt(0)
 
 	bigIntPool = sync.Pool{New: func() interface{} { return new(big.Int) }}
@@ -35,6 +36,12 @@ func (u *EstimateUsecase) parseAmount(amountStr string) (*big.Int, error) {
 }
 
 func (u *EstimateUsecase) calculateAMMOutput(input, reserveIn, reserveOut *big.Int) (*big.Int, error) {
+	return u.calculateAMMOutputWithFee(input, reserveIn, reserveOut, feeNumerator, feeDenominator)
+}
+
+// calculateAMMOutputWithFee applies the constant-product formula, keeping
+// numerator/denominator of every input unit after the swap fee.
+func (u *EstimateUsecase) calculateAMMOutputWithFee(input, reserveIn, reserveOut, numerator, denominator *big.Int) (*big.Int, error) {
 	if input == nil || reserveIn == nil || reserveOut == nil {
 		return nil, fmt.Errorf("nil input/reserves")
 	}
@@ -62,11 +69,11 @@ func (u *EstimateUsecase) calculateAMMOutput(input, reserveIn, reserveOut *big.I
 		putTmp(tmpOutput)
 	}()
 
-	tmpInputWithFee.Mul(input, feeNumerator)
+	tmpInputWithFee.Mul(input, numerator)
 
 	tmpNumerator.Mul(tmpInputWithFee, reserveOut)
 
-	tmpReserveInWithFee.Mul(reserveIn, feeDenominator)
+	tmpReserveInWithFee.Mul(reserveIn, denominator)
 
 	tmpDenominator.Add(tmpReserveInWithFee, tmpInputWithFee)
 
@@ -2,51 +2,215 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 
 	"github.com/DiDinar5/1inch_test_task/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/DiDinar5/1inch_test_task/internal/usecase")
+
 type EstimateUsecase struct {
 	ethereumService domain.EthereumServiceInterface
+	nativeToken     string
+	wrappedNative   string
+	guard           *twapGuard
+	twapCache       *twapCache
+	observer        QuoteObserver
+	feeBps          uint64
+	factoryFees     map[string]uint64
+}
+
+type Option func(*EstimateUsecase)
+
+// QuoteObserver is told how every estimate ended, e.g. to export metrics.
+type QuoteObserver interface {
+	ObserveQuote(outcome string)
+}
+
+func WithQuoteObserver(observer QuoteObserver) Option {
+	return func(u *EstimateUsecase) {
+		u.observer = observer
+	}
 }
 
-func NewEstimateUsecase(ethereumService domain.EthereumServiceInterface) *EstimateUsecase {
-	return &EstimateUsecase{
+func WithNativeToken(nativeToken, wrappedNative string) Option {
+	return func(u *EstimateUsecase) {
+		if nativeToken != "" {
+			u.nativeToken = nativeToken
+		}
+		u.wrappedNative = wrappedNative
+	}
+}
+
+func NewEstimateUsecase(ethereumService domain.EthereumServiceInterface, opts ...Option) *EstimateUsecase {
+	u := &EstimateUsecase{
 		ethereumService: ethereumService,
+		nativeToken:     domain.NativeTokenAddress,
+		feeBps:          UniswapV2FeeBps,
+		twapCache:       newTWAPCache(),
 	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
 }
 
 func (u *EstimateUsecase) Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
-	poolReserves, err := u.ethereumService.GetPoolReserves(ctx, req.Pool)
+	ctx, span := tracer.Start(ctx, "EstimateUsecase.Estimate", trace.WithAttributes(
+		attribute.String("pool.address", req.Pool),
+		attribute.String("token.src", req.Src),
+		attribute.String("token.dst", req.Dst),
+		attribute.Bool("quorum", req.Quorum),
+	))
+	defer span.End()
+
+	response, err := u.estimate(ctx, req)
+
+	outcome := quoteOutcome(err)
+	if u.observer != nil {
+		u.observer.ObserveQuote(outcome)
+	}
+
+	span.SetAttributes(attribute.String("outcome", outcome))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("block.number", int64(response.BlockNumber)), attribute.Bool("cached", response.Cached))
+	}
+
+	return response, err
+}
+
+func (u *EstimateUsecase) estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
+	getReserves := u.ethereumService.GetPoolReserves
+	if req.Quorum {
+		getReserves = u.ethereumService.GetPoolReservesQuorum
+	}
+
+	poolReserves, err := getReserves(ctx, req.Pool)
 	if err != nil {
 		return domain.EstimateResponse{}, fmt.Errorf("failed to get pool reserves: %w", err)
 	}
 
+	feeBps, err := u.poolFeeBps(ctx, req.Pool)
+	if err != nil {
+		return domain.EstimateResponse{}, err
+	}
+
+	response, err := u.quote(req, poolReserves, feeBps)
+	if err != nil {
+		return domain.EstimateResponse{}, err
+	}
+
+	if err := u.checkSpotPrice(ctx, req, poolReserves, &response); err != nil {
+		return domain.EstimateResponse{}, err
+	}
+
+	return response, nil
+}
+
+// quoteOutcome names the reason an estimate failed, most specific first.
+func quoteOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, domain.ErrTokenNotInPool):
+		return "token_not_in_pool"
+	case errors.Is(err, domain.ErrExecutionReverted):
+		return "reverted"
+	case errors.Is(err, domain.ErrInvalidRequest):
+		return "invalid_request"
+	case errors.Is(err, domain.ErrPriceDeviation):
+		return "price_deviation"
+	case errors.Is(err, domain.ErrQuorumNotReached):
+		return "quorum_not_reached"
+	case errors.Is(err, domain.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return "upstream_unavailable"
+	default:
+		return "internal"
+	}
+}
+
+func (u *EstimateUsecase) quote(req domain.EstimateRequest, poolReserves *domain.PoolReserves, feeBps uint64) (domain.EstimateResponse, error) {
 	srcAmount, err := u.parseAmount(req.SrcAmount)
 	if err != nil {
 		return domain.EstimateResponse{}, fmt.Errorf("failed to parse source amount: %w", err)
 	}
 
+	src, err := u.resolveToken(req.Src)
+	if err != nil {
+		return domain.EstimateResponse{}, err
+	}
+
+	dst, err := u.resolveToken(req.Dst)
+	if err != nil {
+		return domain.EstimateResponse{}, err
+	}
+
 	var reserveIn, reserveOut *big.Int
-	if strings.EqualFold(req.Src, poolReserves.Token0) {
+	switch {
+	case strings.EqualFold(src, poolReserves.Token0) && strings.EqualFold(dst, poolReserves.Token1):
 		reserveIn = poolReserves.Reserve0
 		reserveOut = poolReserves.Reserve1
-	} else {
+	case strings.EqualFold(src, poolReserves.Token1) && strings.EqualFold(dst, poolReserves.Token0):
 		reserveIn = poolReserves.Reserve1
 		reserveOut = poolReserves.Reserve0
+	default:
+		return domain.EstimateResponse{}, fmt.Errorf("%w: pool %s holds %s/%s, requested %s -> %s",
+			domain.ErrTokenNotInPool, req.Pool, poolReserves.Token0, poolReserves.Token1, req.Src, req.Dst)
 	}
 
-	dstAmount, err := u.calculateAMMOutput(srcAmount, reserveIn, reserveOut)
+	numerator := new(big.Int).SetUint64(FeeBpsDenominator - feeBps)
+	dstAmount, err := u.calculateAMMOutputWithFee(srcAmount, reserveIn, reserveOut, numerator, bpsDenominator)
 	if err != nil {
 		return domain.EstimateResponse{}, fmt.Errorf("failed to calculate AMM output: %w", err)
 	}
 
-	dstAmountStr := dstAmount.String()
+	response := domain.EstimateResponse{
+		Src:         req.Src,
+		Dst:         req.Dst,
+		DstAmount:   dstAmount.String(),
+		BlockNumber: poolReserves.BlockNumber,
+		Cached:      poolReserves.Cached,
+		Quorum:      poolReserves.Quorum,
+	}
+
+	if u.isNative(req.Src) {
+		response.Value = srcAmount.String()
+	}
+
+	return response, nil
+}
+
+func (u *EstimateUsecase) isNative(token string) bool {
+	return strings.EqualFold(strings.TrimSpace(token), u.nativeToken)
+}
+
+// resolveToken maps the native pseudo-address to the wrapped-native token used by pools.
+func (u *EstimateUsecase) resolveToken(token string) (string, error) {
+	if !u.isNative(token) {
+		return strings.TrimSpace(token), nil
+	}
+
+	if u.wrappedNative == "" {
+		return "", fmt.Errorf("%w: native token is not supported on this chain", domain.ErrInvalidRequest)
+	}
 
-	return domain.EstimateResponse{
-		DstAmount: dstAmountStr,
-	}, nil
+	return u.wrappedNative, nil
 }
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/DiDinar5/1inch_test_task/domain"
@@ -11,12 +12,63 @@ import (
 type mockEthereumService struct {
 	poolReserves *domain.PoolReserves
 	error        error
+	heads        chan uint64
+	cumulative   map[uint64]*domain.CumulativePrices
+	blockAt      uint64
+	twapError    error
+	healthChecks []domain.HealthCheck
+	factory      string
+
+	cumulativeReads []uint64
+	blockAtCalls    int
 }
 
 func (m *mockEthereumService) GetPoolReserves(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
 	return m.poolReserves, m.error
 }
 
+func (m *mockEthereumService) GetPoolReservesQuorum(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
+	return m.poolReserves, m.error
+}
+
+func (m *mockEthereumService) GetPoolFactory(ctx context.Context, poolAddress string) (string, error) {
+	return m.factory, m.error
+}
+
+func (m *mockEthereumService) SubscribeHeads(ctx context.Context) <-chan uint64 {
+	return m.heads
+}
+
+func (m *mockEthereumService) ChainStatus(ctx context.Context) domain.ChainStatus {
+	return domain.ChainStatus{}
+}
+
+func (m *mockEthereumService) GetCumulativePrices(ctx context.Context, poolAddress string, blockNumber uint64) (*domain.CumulativePrices, error) {
+	if m.twapError != nil {
+		return nil, m.twapError
+	}
+	m.cumulativeReads = append(m.cumulativeReads, blockNumber)
+
+	prices, ok := m.cumulative[blockNumber]
+	if !ok {
+		return nil, fmt.Errorf("no cumulative prices at block %d", blockNumber)
+	}
+	return prices, nil
+}
+
+func (m *mockEthereumService) ProviderStatus(ctx context.Context) []domain.ProviderStatus {
+	return nil
+}
+
+func (m *mockEthereumService) HealthChecks(ctx context.Context) []domain.HealthCheck {
+	return m.healthChecks
+}
+
+func (m *mockEthereumService) BlockNumberAt(ctx context.Context, timestamp uint64) (uint64, error) {
+	m.blockAtCalls++
+	return m.blockAt, m.twapError
+}
+
 func TestEstimate(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -90,6 +142,40 @@ func TestEstimate(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Token not in pool",
+			request: domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       "0x3333333333333333333333333333333333333333",
+				Dst:       "0x2222222222222222222222222222222222222222",
+				SrcAmount: "1000000000000000000",
+			},
+			mockReserves: &domain.PoolReserves{
+				Reserve0:    bigIntFromString("10000000000000000000"),
+				Reserve1:    bigIntFromString("20000000000000000000"),
+				Token0:      "0x1111111111111111111111111111111111111111",
+				Token1:      "0x2222222222222222222222222222222222222222",
+				BlockNumber: 12345,
+			},
+			expectError: true,
+		},
+		{
+			name: "Same src and dst",
+			request: domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       "0x1111111111111111111111111111111111111111",
+				Dst:       "0x1111111111111111111111111111111111111111",
+				SrcAmount: "1000000000000000000",
+			},
+			mockReserves: &domain.PoolReserves{
+				Reserve0:    bigIntFromString("10000000000000000000"),
+				Reserve1:    bigIntFromString("20000000000000000000"),
+				Token0:      "0x1111111111111111111111111111111111111111",
+				Token1:      "0x2222222222222222222222222222222222222222",
+				BlockNumber: 12345,
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,3 +206,140 @@ func TestEstimate(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateNativeToken(t *testing.T) {
+	const wrappedNative = "0x1111111111111111111111111111111111111111"
+
+	mockService := &mockEthereumService{
+		poolReserves: &domain.PoolReserves{
+			Reserve0:    bigIntFromString("10000000000000000000"),
+			Reserve1:    bigIntFromString("20000000000000000000"),
+			Token0:      wrappedNative,
+			Token1:      "0x2222222222222222222222222222222222222222",
+			BlockNumber: 12345,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		wrappedNative  string
+		request        domain.EstimateRequest
+		expectedAmount string
+		expectedValue  string
+		expectError    bool
+	}{
+		{
+			name:          "Native to token",
+			wrappedNative: wrappedNative,
+			request: domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
+				Dst:       "0x2222222222222222222222222222222222222222",
+				SrcAmount: "1000000000000000000",
+			},
+			expectedAmount: "1813221787760298263",
+			expectedValue:  "1000000000000000000",
+		},
+		{
+			name:          "Token to native",
+			wrappedNative: wrappedNative,
+			request: domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       "0x2222222222222222222222222222222222222222",
+				Dst:       domain.NativeTokenAddress,
+				SrcAmount: "2000000000000000000",
+			},
+			expectedAmount: "906610893880149131",
+		},
+		{
+			name: "Wrapped native not configured",
+			request: domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       domain.NativeTokenAddress,
+				Dst:       "0x2222222222222222222222222222222222222222",
+				SrcAmount: "1000000000000000000",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewEstimateUsecase(mockService, WithNativeToken("", tt.wrappedNative))
+			result, err := usecase.Estimate(context.Background(), tt.request)
+
+			if tt.expectError {
+				if !errors.Is(err, domain.ErrInvalidRequest) {
+					t.Errorf("Expected invalid request error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result.DstAmount != tt.expectedAmount {
+				t.Errorf("Expected %s, got %s", tt.expectedAmount, result.DstAmount)
+			}
+
+			if result.Src != tt.request.Src || result.Dst != tt.request.Dst {
+				t.Errorf("Expected echoed tokens %s -> %s, got %s -> %s", tt.request.Src, tt.request.Dst, result.Src, result.Dst)
+			}
+
+			if result.Value != tt.expectedValue {
+				t.Errorf("Expected value %q, got %q", tt.expectedValue, result.Value)
+			}
+		})
+	}
+}
+
+type recordingQuoteObserver struct {
+	outcomes []string
+}
+
+func (r *recordingQuoteObserver) ObserveQuote(outcome string) {
+	r.outcomes = append(r.outcomes, outcome)
+}
+
+func TestEstimateReportsOutcome(t *testing.T) {
+	reserves := &domain.PoolReserves{
+		Reserve0:    bigIntFromString("10000000000000000000"),
+		Reserve1:    bigIntFromString("20000000000000000000"),
+		Token0:      "0x1111111111111111111111111111111111111111",
+		Token1:      "0x2222222222222222222222222222222222222222",
+		BlockNumber: 12345,
+	}
+
+	tests := []struct {
+		name     string
+		src      string
+		error    error
+		expected string
+	}{
+		{name: "Success", src: "0x1111111111111111111111111111111111111111", expected: "ok"},
+		{name: "Token not in pool", src: "0x3333333333333333333333333333333333333333", expected: "token_not_in_pool"},
+		{name: "Circuit open", error: domain.ErrCircuitOpen, expected: "circuit_open"},
+		{name: "Deadline", error: context.DeadlineExceeded, expected: "timeout"},
+		{name: "Unclassified", error: errors.New("boom"), expected: "internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := &recordingQuoteObserver{}
+			usecase := NewEstimateUsecase(&mockEthereumService{poolReserves: reserves, error: tt.error}, WithQuoteObserver(observer))
+
+			usecase.Estimate(context.Background(), domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       tt.src,
+				Dst:       "0x2222222222222222222222222222222222222222",
+				SrcAmount: "1000000000000000000",
+			})
+
+			if len(observer.outcomes) != 1 || observer.outcomes[0] != tt.expected {
+				t.Errorf("Expected outcome %q, got %v", tt.expected, observer.outcomes)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+// WithFees sets the swap fee in basis points. When factoryFees is not empty
+// the fee is looked up by the factory that deployed the pool, and pools from
+// any other factory are rejected since their fee is unknown.
+func WithFees(defaultFeeBps uint64, factoryFees map[string]uint64) Option {
+	return func(u *EstimateUsecase) {
+		if defaultFeeBps < FeeBpsDenominator {
+			u.feeBps = defaultFeeBps
+		}
+
+		u.factoryFees = make(map[string]uint64, len(factoryFees))
+		for factory, feeBps := range factoryFees {
+			if feeBps < FeeBpsDenominator {
+				u.factoryFees[strings.ToLower(factory)] = feeBps
+			}
+		}
+	}
+}
+
+func (u *EstimateUsecase) poolFeeBps(ctx context.Context, pool string) (uint64, error) {
+	if len(u.factoryFees) == 0 {
+		return u.feeBps, nil
+	}
+
+	factory, err := u.ethereumService.GetPoolFactory(ctx, pool)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pool factory: %w", err)
+	}
+
+	feeBps, ok := u.factoryFees[strings.ToLower(factory)]
+	if !ok {
+		return 0, fmt.Errorf("%w: pool %s was deployed by unsupported factory %s", domain.ErrInvalidRequest, pool, factory)
+	}
+
+	return feeBps, nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestEstimateAppliesPoolFee(t *testing.T) {
+	const (
+		uniswapFactory = "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"
+		sushiFactory   = "0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac"
+	)
+
+	request := domain.EstimateRequest{
+		Pool:      "0x1234567890123456789012345678901234567890",
+		Src:       "0x1111111111111111111111111111111111111111",
+		Dst:       "0x2222222222222222222222222222222222222222",
+		SrcAmount: "1000000000000000000",
+	}
+	reserves := &domain.PoolReserves{
+		Reserve0: bigIntFromString("10000000000000000000"),
+		Reserve1: bigIntFromString("20000000000000000000"),
+		Token0:   "0x1111111111111111111111111111111111111111",
+		Token1:   "0x2222222222222222222222222222222222222222",
+	}
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		factory        string
+		expectedAmount string
+		expectedErr    error
+	}{
+		{
+			name:           "Default Uniswap V2 fee",
+			expectedAmount: "1813221787760298263",
+		},
+		{
+			name:           "Configured default fee",
+			opts:           []Option{WithFees(25, nil)},
+			expectedAmount: "1814048647419868151",
+		},
+		{
+			name:           "Fee of the pool's factory",
+			opts:           []Option{WithFees(30, map[string]uint64{uniswapFactory: 30, sushiFactory: 100})},
+			factory:        "0xc0aee478e3658e2610c5f7a4a2e1777ce9e4f2ac",
+			expectedAmount: "1801637852593266606",
+		},
+		{
+			name:        "Unsupported factory",
+			opts:        []Option{WithFees(30, map[string]uint64{uniswapFactory: 30})},
+			factory:     sushiFactory,
+			expectedErr: domain.ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockEthereumService{poolReserves: reserves, factory: tt.factory}
+
+			result, err := NewEstimateUsecase(mockService, tt.opts...).Estimate(context.Background(), request)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.DstAmount != tt.expectedAmount {
+				t.Errorf("Expected %s, got %s", tt.expectedAmount, result.DstAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+const basisPoints = 10000
+
+type twapGuard struct {
+	window          time.Duration
+	maxDeviationBps uint64
+}
+
+// WithTWAPGuard compares the spot price of every estimate against the pool's
+// TWAP over window and flags quotes deviating by more than maxDeviationBps.
+func WithTWAPGuard(window time.Duration, maxDeviationBps uint64) Option {
+	return func(u *EstimateUsecase) {
+		if window <= 0 {
+			u.guard = nil
+			return
+		}
+		u.guard = &twapGuard{window: window, maxDeviationBps: maxDeviationBps}
+	}
+}
+
+// checkSpotPrice annotates response with the spot-vs-TWAP deviation. Strict
+// requests are refused when the deviation is too large or cannot be checked.
+func (u *EstimateUsecase) checkSpotPrice(ctx context.Context, req domain.EstimateRequest, poolReserves *domain.PoolReserves, response *domain.EstimateResponse) error {
+	if u.guard == nil {
+		return nil
+	}
+
+	if poolReserves.Reserve0.Sign() == 0 || poolReserves.Reserve1.Sign() == 0 {
+		return nil
+	}
+
+	observation, err := u.observeTWAP(ctx, req.Pool, u.guard.window, poolReserves.BlockNumber)
+	if err != nil {
+		if req.Strict {
+			return fmt.Errorf("failed to check spot price against TWAP: %w", err)
+		}
+		response.Warnings = append(response.Warnings, fmt.Sprintf("TWAP check unavailable: %v", err))
+		return nil
+	}
+
+	spot := fraction(poolReserves.Reserve1, poolReserves.Reserve0)
+	deviation := deviationBps(spot, observation.price0)
+	response.TWAPDeviationBps = &deviation
+
+	if deviation <= u.guard.maxDeviationBps {
+		return nil
+	}
+
+	if req.Strict {
+		return fmt.Errorf("%w: %d bps from the %s TWAP exceeds %d bps",
+			domain.ErrPriceDeviation, deviation, u.guard.window, u.guard.maxDeviationBps)
+	}
+
+	response.Warnings = append(response.Warnings, fmt.Sprintf("spot price deviates %d bps from the %s TWAP (limit %d bps)",
+		deviation, u.guard.window, u.guard.maxDeviationBps))
+
+	return nil
+}
+
+func deviationBps(spot, twap *big.Int) uint64 {
+	diff := new(big.Int).Sub(spot, twap)
+	diff.Abs(diff).Mul(diff, big.NewInt(basisPoints)).Quo(diff, twap)
+
+	if !diff.IsUint64() {
+		return ^uint64(0)
+	}
+
+	return diff.Uint64()
+}
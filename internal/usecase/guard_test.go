@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestEstimateTWAPGuard(t *testing.T) {
+	tests := []struct {
+		name              string
+		reserve1          string
+		strict            bool
+		twapError         error
+		expectedDeviation uint64
+		expectWarning     bool
+		expectError       error
+	}{
+		{
+			name:              "Spot matches TWAP",
+			reserve1:          "30000000000000000000",
+			expectedDeviation: 0,
+		},
+		{
+			name:              "Small deviation within limit",
+			reserve1:          "31000000000000000000",
+			expectedDeviation: 333,
+		},
+		{
+			name:              "Manipulated pool warns",
+			reserve1:          "40000000000000000000",
+			expectedDeviation: 3333,
+			expectWarning:     true,
+		},
+		{
+			name:        "Manipulated pool refused when strict",
+			reserve1:    "40000000000000000000",
+			strict:      true,
+			expectError: domain.ErrPriceDeviation,
+		},
+		{
+			name:          "TWAP unavailable warns",
+			reserve1:      "30000000000000000000",
+			twapError:     errors.New("archive node required"),
+			expectWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockEthereumService{
+				poolReserves: &domain.PoolReserves{
+					Reserve0:    bigIntFromString("10000000000000000000"),
+					Reserve1:    bigIntFromString(tt.reserve1),
+					Token0:      "0x1111111111111111111111111111111111111111",
+					Token1:      "0x2222222222222222222222222222222222222222",
+					BlockNumber: 150,
+				},
+				blockAt:    100,
+				cumulative: guardFixture(),
+				twapError:  tt.twapError,
+			}
+
+			usecase := NewEstimateUsecase(mockService, WithTWAPGuard(10*time.Minute, 500))
+			result, err := usecase.Estimate(context.Background(), domain.EstimateRequest{
+				Pool:      "0x1234567890123456789012345678901234567890",
+				Src:       "0x1111111111111111111111111111111111111111",
+				Dst:       "0x2222222222222222222222222222222222222222",
+				SrcAmount: "1000000000000000000",
+				Strict:    tt.strict,
+			})
+
+			if tt.expectError != nil {
+				if !errors.Is(err, tt.expectError) {
+					t.Errorf("Expected %v, got %v", tt.expectError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if hasWarning := len(result.Warnings) > 0; hasWarning != tt.expectWarning {
+				t.Errorf("Expected warning %v, got %v", tt.expectWarning, result.Warnings)
+			}
+
+			if tt.twapError != nil {
+				return
+			}
+
+			if result.TWAPDeviationBps == nil || *result.TWAPDeviationBps != tt.expectedDeviation {
+				t.Errorf("Expected deviation %d, got %v", tt.expectedDeviation, result.TWAPDeviationBps)
+			}
+		})
+	}
+}
+
+// guardFixture serves the fixture's latest observation at the reserves' block.
+func guardFixture() map[uint64]*domain.CumulativePrices {
+	cumulative := twapFixture()
+	cumulative[150] = cumulative[0]
+	delete(cumulative, 0)
+	return cumulative
+}
+
+func TestTWAPGuardCachesObservations(t *testing.T) {
+	mockService := &mockEthereumService{
+		poolReserves: &domain.PoolReserves{
+			Reserve0:    bigIntFromString("10000000000000000000"),
+			Reserve1:    bigIntFromString("30000000000000000000"),
+			Token0:      "0x1111111111111111111111111111111111111111",
+			Token1:      "0x2222222222222222222222222222222222222222",
+			BlockNumber: 150,
+		},
+		blockAt:    100,
+		cumulative: guardFixture(),
+	}
+
+	usecase := NewEstimateUsecase(mockService, WithTWAPGuard(10*time.Minute, 500))
+	for range 3 {
+		result, err := usecase.Estimate(context.Background(), domain.EstimateRequest{
+			Pool:      "0x1234567890123456789012345678901234567890",
+			Src:       "0x1111111111111111111111111111111111111111",
+			Dst:       "0x2222222222222222222222222222222222222222",
+			SrcAmount: "1000000000000000000",
+		})
+		if err != nil || result.TWAPDeviationBps == nil {
+			t.Fatalf("Expected a checked estimate, got %+v (%v)", result, err)
+		}
+	}
+
+	if len(mockService.cumulativeReads) != 2 || mockService.cumulativeReads[0] != 150 || mockService.cumulativeReads[1] != 100 {
+		t.Errorf("Expected one read at the reserves' block and one at the window start, got %v", mockService.cumulativeReads)
+	}
+	if mockService.blockAtCalls != 1 {
+		t.Errorf("Expected the start block search to run once, got %d", mockService.blockAtCalls)
+	}
+}
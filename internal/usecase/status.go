@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func (u *EstimateUsecase) ChainStatus(ctx context.Context) domain.ChainStatus {
+	return u.ethereumService.ChainStatus(ctx)
+}
+
+func (u *EstimateUsecase) ProviderStatus(ctx context.Context) []domain.ProviderStatus {
+	return u.ethereumService.ProviderStatus(ctx)
+}
+
+// Readiness reports whether every chain check passes.
+func (u *EstimateUsecase) Readiness(ctx context.Context) domain.Readiness {
+	checks := u.ethereumService.HealthChecks(ctx)
+
+	ready := len(checks) > 0
+	for _, check := range checks {
+		ready = ready && check.Healthy
+	}
+
+	return domain.Readiness{Ready: ready, Checks: checks}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   []domain.HealthCheck
+		expected bool
+	}{
+		{name: "All checks pass", checks: []domain.HealthCheck{{Name: "rpc", Healthy: true}, {Name: "chain_id", Healthy: true}}, expected: true},
+		{name: "One check fails", checks: []domain.HealthCheck{{Name: "rpc", Healthy: true}, {Name: "chain_id"}}},
+		{name: "No checks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewEstimateUsecase(&mockEthereumService{healthChecks: tt.checks})
+
+			readiness := usecase.Readiness(context.Background())
+			if readiness.Ready != tt.expected {
+				t.Errorf("Expected ready=%v, got %+v", tt.expected, readiness)
+			}
+			if len(readiness.Checks) != len(tt.checks) {
+				t.Errorf("Expected %d checks reported, got %d", len(tt.checks), len(readiness.Checks))
+			}
+		})
+	}
+}
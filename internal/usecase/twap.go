@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+const (
+	maxTWAPWindow    = 7 * 24 * time.Hour
+	twapPrecision    = 18
+	resolutionBits   = 112
+	twapStartBuckets = 60
+	maxTWAPCached    = 4096
+)
+
+var uint256Modulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+type twapObservation struct {
+	start  *domain.CumulativePrices
+	end    *domain.CumulativePrices
+	price0 *big.Int
+	price1 *big.Int
+}
+
+// twapCache keeps the guard from repeating the start block search and the
+// historic reads on every estimate. Entries are keyed by block number, which
+// is safe for window starts far below the head; end observations at a block
+// that gets reorged out are served until the cache fills up.
+type twapCache struct {
+	mu           sync.Mutex
+	blocks       map[uint64]uint64
+	observations map[twapCacheKey]*domain.CumulativePrices
+}
+
+type twapCacheKey struct {
+	pool  string
+	block uint64
+}
+
+func newTWAPCache() *twapCache {
+	return &twapCache{
+		blocks:       make(map[uint64]uint64),
+		observations: make(map[twapCacheKey]*domain.CumulativePrices),
+	}
+}
+
+func (u *EstimateUsecase) blockNumberAt(ctx context.Context, timestamp uint64) (uint64, error) {
+	u.twapCache.mu.Lock()
+	block, ok := u.twapCache.blocks[timestamp]
+	u.twapCache.mu.Unlock()
+	if ok {
+		return block, nil
+	}
+
+	block, err := u.ethereumService.BlockNumberAt(ctx, timestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	u.twapCache.mu.Lock()
+	if len(u.twapCache.blocks) >= maxTWAPCached {
+		clear(u.twapCache.blocks)
+	}
+	u.twapCache.blocks[timestamp] = block
+	u.twapCache.mu.Unlock()
+
+	return block, nil
+}
+
+// cumulativePrices reads the pool's accumulators at block; reads at the
+// latest block (0) are not cached.
+func (u *EstimateUsecase) cumulativePrices(ctx context.Context, pool string, block uint64) (*domain.CumulativePrices, error) {
+	key := twapCacheKey{pool: strings.ToLower(pool), block: block}
+	if block != 0 {
+		u.twapCache.mu.Lock()
+		prices, ok := u.twapCache.observations[key]
+		u.twapCache.mu.Unlock()
+		if ok {
+			return prices, nil
+		}
+	}
+
+	prices, err := u.ethereumService.GetCumulativePrices(ctx, pool, block)
+	if err != nil || block == 0 {
+		return prices, err
+	}
+
+	u.twapCache.mu.Lock()
+	if len(u.twapCache.observations) >= maxTWAPCached {
+		clear(u.twapCache.observations)
+	}
+	u.twapCache.observations[key] = prices
+	u.twapCache.mu.Unlock()
+
+	return prices, nil
+}
+
+func (u *EstimateUsecase) TWAP(ctx context.Context, req domain.TWAPRequest) (domain.TWAPResponse, error) {
+	window, err := parseWindow(req.Window)
+	if err != nil {
+		return domain.TWAPResponse{}, err
+	}
+
+	observation, err := u.observeTWAP(ctx, req.Pool, window, 0)
+	if err != nil {
+		return domain.TWAPResponse{}, err
+	}
+
+	start, end := observation.start, observation.end
+
+	return domain.TWAPResponse{
+		Pool:            req.Pool,
+		Token0:          end.Token0,
+		Token1:          end.Token1,
+		WindowSeconds:   uint64(window / time.Second),
+		ElapsedSeconds:  end.BlockTimestamp - start.BlockTimestamp,
+		StartBlock:      start.BlockNumber,
+		EndBlock:        end.BlockNumber,
+		StartTimestamp:  start.BlockTimestamp,
+		EndTimestamp:    end.BlockTimestamp,
+		Price0:          formatUQ112x112(observation.price0),
+		Price1:          formatUQ112x112(observation.price1),
+		Price0UQ112x112: observation.price0.String(),
+		Price1UQ112x112: observation.price1.String(),
+	}, nil
+}
+
+// observeTWAP measures the TWAP over window ending at endBlock, or at the
+// latest block when endBlock is 0. The window start is rounded down to a
+// bucket of twapStartBuckets per window so repeated observations share it.
+func (u *EstimateUsecase) observeTWAP(ctx context.Context, pool string, window time.Duration, endBlock uint64) (*twapObservation, error) {
+	end, err := u.cumulativePrices(ctx, pool, endBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cumulative prices: %w", err)
+	}
+
+	seconds := uint64(window / time.Second)
+	if end.BlockTimestamp <= seconds {
+		return nil, fmt.Errorf("%w: window reaches before genesis", domain.ErrInvalidRequest)
+	}
+
+	target := end.BlockTimestamp - seconds
+	target -= target % max(seconds/twapStartBuckets, 1)
+
+	startBlock, err := u.blockNumberAt(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find window start block: %w", err)
+	}
+
+	start, err := u.cumulativePrices(ctx, pool, startBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cumulative prices at block %d: %w", startBlock, err)
+	}
+
+	if start.BlockTimestamp >= end.BlockTimestamp {
+		return nil, fmt.Errorf("%w: window is shorter than the block interval", domain.ErrInvalidRequest)
+	}
+
+	price0, price1 := computeTWAP(start, end)
+	if price0.Sign() == 0 || price1.Sign() == 0 {
+		return nil, fmt.Errorf("%w: pool had no liquidity during the window", domain.ErrInvalidRequest)
+	}
+
+	return &twapObservation{start: start, end: end, price0: price0, price1: price1}, nil
+}
+
+// parseWindow accepts a Go duration ("30m") or a number of seconds.
+func parseWindow(window string) (time.Duration, error) {
+	window = strings.TrimSpace(window)
+
+	var duration time.Duration
+	if seconds, err := strconv.ParseUint(window, 10, 64); err == nil {
+		if seconds > uint64(maxTWAPWindow/time.Second) {
+			return 0, fmt.Errorf("%w: window must not exceed %s", domain.ErrInvalidRequest, maxTWAPWindow)
+		}
+		duration = time.Duration(seconds) * time.Second
+	} else {
+		parsed, err := time.ParseDuration(window)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid window: %s", domain.ErrInvalidRequest, window)
+		}
+		duration = parsed
+	}
+
+	if duration < time.Second {
+		return 0, fmt.Errorf("%w: window must be at least one second", domain.ErrInvalidRequest)
+	}
+	if duration > maxTWAPWindow {
+		return 0, fmt.Errorf("%w: window must not exceed %s", domain.ErrInvalidRequest, maxTWAPWindow)
+	}
+
+	return duration, nil
+}
+
+// currentCumulativePrices mirrors UniswapV2OracleLibrary: when the pair has
+// not been touched in the current block, the accumulators are extended to the
+// block timestamp using the stored reserves.
+func currentCumulativePrices(prices *domain.CumulativePrices) (*big.Int, *big.Int) {
+	price0 := new(big.Int).Set(prices.Price0CumulativeLast)
+	price1 := new(big.Int).Set(prices.Price1CumulativeLast)
+
+	blockTimestamp := uint32(prices.BlockTimestamp)
+	if blockTimestamp == prices.BlockTimestampLast ||
+		prices.Reserve0.Sign() == 0 || prices.Reserve1.Sign() == 0 {
+		return price0, price1
+	}
+
+	// Overflow is desired, as in the pair contract.
+	timeElapsed := big.NewInt(int64(blockTimestamp - prices.BlockTimestampLast))
+
+	price0.Add(price0, new(big.Int).Mul(fraction(prices.Reserve1, prices.Reserve0), timeElapsed))
+	price1.Add(price1, new(big.Int).Mul(fraction(prices.Reserve0, prices.Reserve1), timeElapsed))
+
+	return price0.Mod(price0, uint256Modulus), price1.Mod(price1, uint256Modulus)
+}
+
+// computeTWAP returns the average UQ112x112 prices between two observations,
+// or nil when no time has elapsed.
+func computeTWAP(start, end *domain.CumulativePrices) (*big.Int, *big.Int) {
+	if end.BlockTimestamp <= start.BlockTimestamp {
+		return nil, nil
+	}
+	elapsed := new(big.Int).SetUint64(end.BlockTimestamp - start.BlockTimestamp)
+
+	start0, start1 := currentCumulativePrices(start)
+	end0, end1 := currentCumulativePrices(end)
+
+	price0 := subUint256(end0, start0)
+	price1 := subUint256(end1, start1)
+
+	return price0.Quo(price0, elapsed), price1.Quo(price1, elapsed)
+}
+
+func fraction(numerator, denominator *big.Int) *big.Int {
+	value := new(big.Int).Lsh(numerator, resolutionBits)
+	return value.Quo(value, denominator)
+}
+
+func subUint256(a, b *big.Int) *big.Int {
+	diff := new(big.Int).Sub(a, b)
+	if diff.Sign() < 0 {
+		diff.Add(diff, uint256Modulus)
+	}
+	return diff
+}
+
+func formatUQ112x112(value *big.Int) string {
+	denominator := new(big.Int).Lsh(big.NewInt(1), resolutionBits)
+	return new(big.Rat).SetFrac(value, denominator).FloatString(twapPrecision)
+}
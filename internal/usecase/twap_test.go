@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+func q112(value int64) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(value), 112)
+}
+
+func TestComputeTWAP(t *testing.T) {
+	nearWrap := new(big.Int).Sub(uint256Modulus, new(big.Int).Mul(q112(2), big.NewInt(100)))
+
+	tests := []struct {
+		name     string
+		start    *domain.CumulativePrices
+		end      *domain.CumulativePrices
+		expected string
+	}{
+		{
+			name: "Accumulators updated in both blocks",
+			start: &domain.CumulativePrices{
+				BlockTimestamp: 1000, BlockTimestampLast: 1000,
+				Price0CumulativeLast: big.NewInt(0), Price1CumulativeLast: big.NewInt(0),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			end: &domain.CumulativePrices{
+				BlockTimestamp: 1100, BlockTimestampLast: 1100,
+				Price0CumulativeLast: new(big.Int).Mul(q112(2), big.NewInt(100)), Price1CumulativeLast: q112(50),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			expected: "2.000000000000000000",
+		},
+		{
+			name: "Counterfactual extension to block timestamp",
+			start: &domain.CumulativePrices{
+				BlockTimestamp: 1000, BlockTimestampLast: 1000,
+				Price0CumulativeLast: big.NewInt(0), Price1CumulativeLast: big.NewInt(0),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			end: &domain.CumulativePrices{
+				BlockTimestamp: 1100, BlockTimestampLast: 1050,
+				Price0CumulativeLast: new(big.Int).Mul(q112(2), big.NewInt(50)), Price1CumulativeLast: q112(25),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			expected: "2.000000000000000000",
+		},
+		{
+			name: "Accumulator overflow",
+			start: &domain.CumulativePrices{
+				BlockTimestamp: 1000, BlockTimestampLast: 1000,
+				Price0CumulativeLast: nearWrap, Price1CumulativeLast: big.NewInt(0),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			end: &domain.CumulativePrices{
+				BlockTimestamp: 1200, BlockTimestampLast: 1200,
+				Price0CumulativeLast: new(big.Int).Mul(q112(2), big.NewInt(100)), Price1CumulativeLast: q112(100),
+				Reserve0: big.NewInt(1), Reserve1: big.NewInt(2),
+			},
+			expected: "2.000000000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price0, _ := computeTWAP(tt.start, tt.end)
+			if price0 == nil {
+				t.Fatalf("Expected price but got nil")
+			}
+
+			if result := formatUQ112x112(price0); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      string
+		expected    string
+		expectError bool
+	}{
+		{name: "Duration", window: "30m", expected: "30m0s"},
+		{name: "Seconds", window: "600", expected: "10m0s"},
+		{name: "Zero", window: "0", expectError: true},
+		{name: "Too long", window: "200h", expectError: true},
+		{name: "Garbage", window: "soon", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseWindow(tt.window)
+
+			if tt.expectError {
+				if !errors.Is(err, domain.ErrInvalidRequest) {
+					t.Errorf("Expected invalid request error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// twapFixture returns cumulative prices for a pool whose token0 traded at 3
+// token1 over the ten minutes ending at the latest block.
+func twapFixture() map[uint64]*domain.CumulativePrices {
+	return map[uint64]*domain.CumulativePrices{
+		0: {
+			Token0: "0xToken0", Token1: "0xToken1",
+			BlockNumber: 150, BlockTimestamp: 1600, BlockTimestampLast: 1600,
+			Price0CumulativeLast: new(big.Int).Mul(q112(3), big.NewInt(600)),
+			Price1CumulativeLast: q112(200),
+			Reserve0:             big.NewInt(1), Reserve1: big.NewInt(3),
+		},
+		100: {
+			Token0: "0xToken0", Token1: "0xToken1",
+			BlockNumber: 100, BlockTimestamp: 1000, BlockTimestampLast: 1000,
+			Price0CumulativeLast: big.NewInt(0), Price1CumulativeLast: big.NewInt(0),
+			Reserve0: big.NewInt(1), Reserve1: big.NewInt(3),
+		},
+	}
+}
+
+func TestTWAP(t *testing.T) {
+	mockService := &mockEthereumService{
+		blockAt:    100,
+		cumulative: twapFixture(),
+	}
+
+	usecase := NewEstimateUsecase(mockService)
+	result, err := usecase.TWAP(context.Background(), domain.TWAPRequest{Pool: "0xPool", Window: "10m"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Price0 != "3.000000000000000000" {
+		t.Errorf("Expected price0 3, got %s", result.Price0)
+	}
+	if result.Price1 != "0.333333333333333333" {
+		t.Errorf("Expected price1 1/3, got %s", result.Price1)
+	}
+	if result.StartBlock != 100 || result.EndBlock != 150 || result.ElapsedSeconds != 600 {
+		t.Errorf("Unexpected window: %+v", result)
+	}
+}
+
+func TestTWAPRejectsPoolWithoutLiquidity(t *testing.T) {
+	empty := func(block, timestamp uint64) *domain.CumulativePrices {
+		return &domain.CumulativePrices{
+			BlockNumber: block, BlockTimestamp: timestamp, BlockTimestampLast: 0,
+			Price0CumulativeLast: big.NewInt(0), Price1CumulativeLast: big.NewInt(0),
+			Reserve0: big.NewInt(0), Reserve1: big.NewInt(0),
+		}
+	}
+
+	mockService := &mockEthereumService{
+		blockAt:    100,
+		cumulative: map[uint64]*domain.CumulativePrices{0: empty(150, 1600), 100: empty(100, 1000)},
+	}
+
+	_, err := NewEstimateUsecase(mockService).TWAP(context.Background(), domain.TWAPRequest{Pool: "0xPool", Window: "10m"})
+	if !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Errorf("Expected invalid request error for a pool without reserves, got %v", err)
+	}
+}
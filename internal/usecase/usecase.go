@@ -4,11 +4,15 @@ import (
 	"github.com/DiDinar5/1inch_test_task/domain"
 )
 
-func NewUsecase(ethereumService domain.EthereumServiceInterface) domain.UsecaseInterface {
-	return NewEstimateUsecase(ethereumService)
+func NewUsecase(ethereumService domain.EthereumServiceInterface, opts ...Option) domain.UsecaseInterface {
+	return NewEstimateUsecase(ethereumService, opts...)
 }
 
 const (
 	UniswapV2FeeNumerator   = 997
 	UniswapV2FeeDenominator = 1000
+
+	// Fees are configured in basis points of the input amount.
+	UniswapV2FeeBps   = 30
+	FeeBpsDenominator = 10000
 )
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+// WatchEstimate emits the current estimate immediately and a fresh one every
+// time the pool's reserves change. The channel is closed when ctx is done.
+func (u *EstimateUsecase) WatchEstimate(ctx context.Context, req domain.EstimateRequest) (<-chan domain.EstimateResponse, error) {
+	heads := u.ethereumService.SubscribeHeads(ctx)
+
+	poolReserves, err := u.ethereumService.GetPoolReserves(ctx, req.Pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool reserves: %w", err)
+	}
+
+	feeBps, err := u.poolFeeBps(ctx, req.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := u.quote(req, poolReserves, feeBps)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan domain.EstimateResponse, 1)
+	updates <- response
+
+	go func() {
+		defer close(updates)
+
+		last := poolReserves
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heads:
+			}
+
+			current, err := u.ethereumService.GetPoolReserves(ctx, req.Pool)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.WarnContext(ctx, "failed to refresh pool reserves", "pool", req.Pool, "error", err)
+				}
+				continue
+			}
+
+			if sameReserves(last, current) {
+				continue
+			}
+			last = current
+
+			response, err := u.quote(req, current, feeBps)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to refresh estimate", "pool", req.Pool, "error", err)
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case updates <- response:
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func sameReserves(a, b *domain.PoolReserves) bool {
+	return a.Reserve0.Cmp(b.Reserve0) == 0 && a.Reserve1.Cmp(b.Reserve1) == 0
+}
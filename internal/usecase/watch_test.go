@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/1inch_test_task/domain"
+)
+
+type sequenceEthereumService struct {
+	mockEthereumService
+
+	mu       sync.Mutex
+	reserves []*domain.PoolReserves
+	heads    chan uint64
+}
+
+func (s *sequenceEthereumService) GetPoolReserves(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.reserves[0]
+	if len(s.reserves) > 1 {
+		s.reserves = s.reserves[1:]
+	}
+	return current, nil
+}
+
+func (s *sequenceEthereumService) SubscribeHeads(ctx context.Context) <-chan uint64 {
+	return s.heads
+}
+
+func TestWatchEstimate(t *testing.T) {
+	reserves := func(reserve0, reserve1 string, block uint64) *domain.PoolReserves {
+		return &domain.PoolReserves{
+			Reserve0:    bigIntFromString(reserve0),
+			Reserve1:    bigIntFromString(reserve1),
+			Token0:      "0x1111111111111111111111111111111111111111",
+			Token1:      "0x2222222222222222222222222222222222222222",
+			BlockNumber: block,
+		}
+	}
+
+	service := &sequenceEthereumService{
+		reserves: []*domain.PoolReserves{
+			reserves("10000000000000000000", "20000000000000000000", 1),
+			reserves("10000000000000000000", "20000000000000000000", 2),
+			reserves("20000000000000000000", "40000000000000000000", 3),
+		},
+		heads: make(chan uint64),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	usecase := NewEstimateUsecase(service)
+	updates, err := usecase.WatchEstimate(ctx, domain.EstimateRequest{
+		Pool:      "0x1234567890123456789012345678901234567890",
+		Src:       "0x1111111111111111111111111111111111111111",
+		Dst:       "0x2222222222222222222222222222222222222222",
+		SrcAmount: "1000000000000000000",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	initial := <-updates
+	if initial.DstAmount != "1813221787760298263" || initial.BlockNumber != 1 {
+		t.Errorf("Unexpected initial estimate: %+v", initial)
+	}
+
+	service.heads <- 2
+	service.heads <- 3
+
+	select {
+	case update := <-updates:
+		if update.BlockNumber != 3 {
+			t.Errorf("Expected update only after reserves changed at block 3, got block %d", update.BlockNumber)
+		}
+		if update.DstAmount != "1899318950326237081" {
+			t.Errorf("Expected 1899318950326237081, got %s", update.DstAmount)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected estimate update after reserves changed")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Errorf("Expected updates channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected updates channel to close after cancellation")
+	}
+}